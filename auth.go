@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	tokenEnv    = "SONAR_TOKEN"
+	userEnv     = "SONAR_USER"
+	passwordEnv = "SONAR_PASSWORD"
+)
+
+// credentials are the sources of the Authorization header sent with every loader request.
+// The first configured source wins: raw header, token, user/password, environment variables.
+type credentials struct {
+	header       string
+	token        string
+	tokenFile    string
+	user         string
+	password     string
+	passwordFile string
+}
+
+// authorization resolves the Authorization header value, empty string means anonymous access
+func (c *credentials) authorization() (string, error) {
+	if c.header != "" {
+		return c.header, nil
+	}
+
+	token, err := valueOrFile(c.token, c.tokenFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token：%w", err)
+	}
+	if token != "" {
+		return tokenAuthorization(token), nil
+	}
+
+	password, err := valueOrFile(c.password, c.passwordFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password：%w", err)
+	}
+	if c.user != "" {
+		// the password defaults to the environment variable like the user does
+		if c.password == "" && c.passwordFile == "" {
+			password = os.Getenv(passwordEnv)
+		}
+		return basicAuthorization(c.user, password), nil
+	}
+	if password != "" {
+		return "", errors.New("password is set without user")
+	}
+
+	if token := os.Getenv(tokenEnv); token != "" {
+		return tokenAuthorization(token), nil
+	}
+	if user := os.Getenv(userEnv); user != "" {
+		return basicAuthorization(user, os.Getenv(passwordEnv)), nil
+	}
+
+	return "", nil
+}
+
+// valueOrFile returns the value or, if it's empty, the content of the file without trailing whitespaces
+func valueOrFile(value, file string) (string, error) {
+	if value != "" && file != "" {
+		return "", errors.New("value and file can't be set together")
+	}
+	if file == "" {
+		return value, nil
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), "\r\n\t "), nil
+}
+
+// tokenAuthorization uses a token as the login with an empty password, which is supported by all SonarQube versions
+func tokenAuthorization(token string) string {
+	return basicAuthorization(token, "")
+}
+
+func basicAuthorization(user, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+password))
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_credentials_authorization(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(secret, []byte("token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		creds   credentials
+		env     map[string]string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "should be anonymous if nothing is set",
+			creds: credentials{},
+			want:  "",
+		},
+		{
+			name:  "should prefer raw header",
+			creds: credentials{header: "Bearer raw", token: "token"},
+			want:  "Bearer raw",
+		},
+		{
+			name:  "should use token as login",
+			creds: credentials{token: "token"},
+			want:  "Basic dG9rZW46",
+		},
+		{
+			name:  "should read token from file",
+			creds: credentials{tokenFile: secret},
+			want:  "Basic dG9rZW46",
+		},
+		{
+			name:  "should use user and password from file",
+			creds: credentials{user: "admin", passwordFile: secret},
+			want:  "Basic YWRtaW46dG9rZW4=",
+		},
+		{
+			name:  "should read password of user from environment",
+			creds: credentials{user: "admin"},
+			env:   map[string]string{passwordEnv: "token"},
+			want:  "Basic YWRtaW46dG9rZW4=",
+		},
+		{
+			name:  "should prefer password flag to environment",
+			creds: credentials{user: "admin", password: "token"},
+			env:   map[string]string{passwordEnv: "other"},
+			want:  "Basic YWRtaW46dG9rZW4=",
+		},
+		{
+			name:    "should fail if password is set without user",
+			creds:   credentials{password: "admin"},
+			wantErr: true,
+		},
+		{
+			name:    "should fail if token and token file are set",
+			creds:   credentials{token: "token", tokenFile: secret},
+			wantErr: true,
+		},
+		{
+			name:  "should fall back to environment token",
+			creds: credentials{},
+			env:   map[string]string{tokenEnv: "token"},
+			want:  "Basic dG9rZW46",
+		},
+		{
+			name:  "should prefer flags to environment",
+			creds: credentials{user: "admin", password: "admin"},
+			env:   map[string]string{tokenEnv: "token"},
+			want:  "Basic YWRtaW46YWRtaW4=",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{tokenEnv, userEnv, passwordEnv} {
+				t.Setenv(key, tt.env[key])
+			}
+			got, err := tt.creds.authorization()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("authorization() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("authorization() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
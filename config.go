@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// goPackageEnv is set by go generate to the name of the package of the file with the directive
+const goPackageEnv = "GOPACKAGE"
+
+// applyConfig sets the flags from the json config file, keys are flag names, e.g. {"target": "9.9", "internal": true}.
+// Flags set on the command line take precedence over the config.
+func applyConfig(fs *flag.FlagSet, path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config：%w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	config := make(map[string]interface{})
+	if err := decoder.Decode(&config); err != nil {
+		return fmt.Errorf("failed to decode config (%s)：%w", path, err)
+	}
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	for name, value := range config {
+		if name == "config" || fs.Lookup(name) == nil {
+			return fmt.Errorf("unknown option %s in config %s", name, path)
+		}
+		if set[name] {
+			continue
+		}
+		switch value.(type) {
+		case string, bool, json.Number:
+		default:
+			return fmt.Errorf("invalid value of option %s in config %s, it must be a string, a number or a bool", name, path)
+		}
+		if err := fs.Set(name, fmt.Sprint(value)); err != nil {
+			return fmt.Errorf("invalid value of option %s in config %s：%w", name, path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_applyConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		args    []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:   "should set flags",
+			config: `{"target": "9.9=defs/9.9.json", "internal": true, "doc-width": 80}`,
+			want:   map[string]string{"target": "9.9=defs/9.9.json", "internal": "true", "doc-width": "80"},
+		},
+		{
+			name:   "should prefer command line flags",
+			config: `{"target": "9.9", "internal": true}`,
+			args:   []string{"-target", "10.0"},
+			want:   map[string]string{"target": "10.0", "internal": "true"},
+		},
+		{name: "should fail on unknown options", config: `{"targets": "9.9"}`, wantErr: true},
+		{name: "should fail on invalid values", config: `{"doc-width": "wide"}`, wantErr: true},
+		{name: "should fail on lists", config: `{"target": ["9.9"]}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(path, []byte(tt.config), 0644); err != nil {
+				t.Fatal(err)
+			}
+			fs := flag.NewFlagSet("", flag.ContinueOnError)
+			fs.String("target", "", "")
+			fs.Bool("internal", false, "")
+			fs.Int("doc-width", 100, "")
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			err := applyConfig(fs, path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for name, want := range tt.want {
+				if got := fs.Lookup(name).Value.String(); got != want {
+					t.Errorf("applyConfig() %s = %v, want %v", name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	cliTemplateName = "cli.tpl"
+	cliDir          = "cmd/sonarctl"
+	cliFileName     = cliDir + "/main.go"
+)
+
+// cliData is passed to the cli template, ImportPath is the import path of the generated client package
+type cliData struct {
+	*APIDefinition
+	ImportPath string
+}
+
+func (g *Generator) renderCLI(in io.Writer, data *cliData) error {
+
+	buff := bytes.NewBuffer([]byte{})
+	buff.WriteString(generatedMarker + "\n\n")
+
+	cliTemplate, err := g.parseTemplate(cliTemplateName)
+	if err != nil {
+		return err
+	}
+
+	if err := cliTemplate.Execute(buff, data); err != nil {
+		return fmt.Errorf("failed to render cli：%w", err)
+	}
+
+	src := buff.Bytes()
+
+	formatted, err := format.Source(src)
+	if err != nil {
+		log.Printf("failed to format source of %s/%s: err:%s", data.PackageName, cliFileName, err.Error())
+		formatted = src
+	}
+
+	_, err = in.Write(formatted)
+	return err
+}
+
+// ErrCLIImport is returned when the import path of the package imported by the command line tool can't be detected,
+// it has to be set with Options.CLIImport
+var ErrCLIImport = errors.New("can't detect import path of the package for the command line tool")
+
+// detectImportPath resolves the import path of the dir from the closest go.mod file
+func detectImportPath(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve import path of %s：%w", dir, err)
+	}
+	for root := abs; ; root = filepath.Dir(root) {
+		module, err := readModulePath(filepath.Join(root, "go.mod"))
+		if err != nil {
+			return "", err
+		}
+		if module != "" {
+			rel, err := filepath.Rel(root, abs)
+			if err != nil {
+				return "", fmt.Errorf("failed to resolve import path of %s：%w", dir, err)
+			}
+			if rel == "." {
+				return module, nil
+			}
+			return module + "/" + filepath.ToSlash(rel), nil
+		}
+		if filepath.Dir(root) == root {
+			return "", fmt.Errorf("failed to resolve import path of %s：go.mod not found", dir)
+		}
+	}
+}
+
+// readModulePath returns the module path declared in the go.mod file, or empty string if the file doesn't exist
+func readModulePath(path string) (string, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s：%w", path, err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "module ") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read %s：%w", path, err)
+	}
+	return "", fmt.Errorf("module path is not declared in %s", path)
+}
@@ -0,0 +1,40 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+)
+
+const (
+	clientTemplateName = "client.tpl"
+	clientFileName     = clientTemplateName + ".go"
+)
+
+func (g *Generator) renderClient(in io.Writer, data *APIDefinition) error {
+
+	buff := bytes.NewBuffer([]byte{})
+	buff.WriteString(generatedMarker + "\n\n")
+
+	clientTemplate, err := g.parseTemplate(clientTemplateName)
+	if err != nil {
+		return err
+	}
+
+	if err := clientTemplate.Execute(buff, data); err != nil {
+		return fmt.Errorf("failed to render client: %w", err)
+	}
+
+	src := moveDocLinks(buff.Bytes())
+
+	formatted, err := format.Source(src)
+	if err != nil {
+		log.Printf("failed to format source of %s/client.go: err:%s", data.PackageName, err.Error())
+		formatted = src
+	}
+
+	_, err = in.Write(formatted)
+	return err
+}
@@ -0,0 +1,564 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	targetDirPermission = 0755
+	filePermission      = 0644
+	// generatedMarker is written as the first line of every generated file,
+	// files starting with it are considered to be owned by the generator
+	generatedMarker = "// Code generated by sonarqube-api-client-gen. DO NOT EDIT."
+	// markdownMarker is the generated marker of markdown files
+	markdownMarker = "<!-- Code generated by sonarqube-api-client-gen. DO NOT EDIT. -->"
+	// manifestFileName is the file listing generated files which can't start with a marker, e.g. json or yaml,
+	// the listed files are owned by the generator like the marked ones
+	manifestFileName = ".sonarqube-api-client-gen"
+	// manifestMarker is the first line of the manifest
+	manifestMarker = "# Code generated by sonarqube-api-client-gen. DO NOT EDIT."
+)
+
+func checkOutput(out string) error {
+	dir, err := os.Open(out)
+	if err != nil {
+		return fmt.Errorf("failed to open target dir (%s)：%w", out, err)
+	}
+	dirInfo, err := dir.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to open target dir (%s)：%w", out, err)
+	}
+	if !dirInfo.IsDir() {
+		return fmt.Errorf("target output is not a directory (%s)：%w", dirInfo.Mode().String(), err)
+	}
+	return nil
+}
+
+// Mode defines what Generate does with the rendered files
+type Mode int
+
+const (
+	// ModeWrite writes rendered files to the disk
+	ModeWrite Mode = iota
+	// ModeDryRun only reports which files would be created, updated or removed
+	ModeDryRun
+	// ModeCheck fails if the files on the disk differ from the rendered ones
+	ModeCheck
+)
+
+// ChangeOp is what the generation does with a file
+type ChangeOp string
+
+const (
+	OpCreate ChangeOp = "create"
+	OpUpdate ChangeOp = "update"
+	OpRemove ChangeOp = "remove"
+	opKeep   ChangeOp = "keep"
+)
+
+// Change is a file created, updated or removed by Generate, in a dry run or a check the file is left as it is
+type Change struct {
+	// Path is the path of the file relative to Options.Out
+	Path string
+	Op   ChangeOp
+}
+
+func (c Change) String() string {
+	return string(c.Op) + " " + c.Path
+}
+
+type fileChange struct {
+	// dir is the package directory relative to the out directory, empty if the package is generated in place
+	dir     string
+	name    string
+	op      ChangeOp
+	content []byte
+}
+
+// path returns the path of the file relative to the out directory
+func (fc *fileChange) path() string {
+	return path.Join(fc.dir, fc.name)
+}
+
+func (fc *fileChange) String() string {
+	return Change{Path: fc.path(), Op: fc.op}.String()
+}
+
+// output formats
+const (
+	FormatGo       = "go"
+	FormatOpenAPI  = "openapi"
+	FormatMarkdown = "markdown"
+)
+
+// generateCode generates the client package (or the document in another format) in the out directory,
+// if cliImport is set the command line tool importing the package from cliImport is generated too
+func (g *Generator) generateCode(def *APIDefinition, cliImport string) ([]*fileChange, error) {
+
+	if err := checkOutput(g.opts.Out); err != nil {
+		return nil, err
+	}
+
+	path := g.packageDir(def.PackageName)
+
+	var files map[string][]byte
+	var err error
+	switch g.opts.Format {
+	case FormatGo:
+		var ext *extensions
+		if ext, err = loadExtensions(path); err != nil {
+			return nil, err
+		}
+		applyExtensions(def, ext)
+		files, err = g.renderFiles(def, cliImport)
+	case FormatOpenAPI:
+		buff := new(bytes.Buffer)
+		err = renderOpenAPI(buff, def)
+		files = map[string][]byte{openAPIFileName: buff.Bytes()}
+	case FormatMarkdown:
+		files, err = g.renderMarkdown(def)
+	default:
+		err = fmt.Errorf("unknown format %s", g.opts.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	stampFiles(files, g.stamp(def))
+
+	return g.writeChanges(def.PackageName, files, nil)
+}
+
+// packageDir returns the directory of the package, the out directory itself if the package is generated in place
+func (g *Generator) packageDir(name string) string {
+	if g.opts.InPlace {
+		return g.opts.Out
+	}
+	return g.opts.Out + "/" + name
+}
+
+// writeChanges writes the rendered files of the package unless it's a dry run or a check, the planned changes are returned,
+// packages are the version packages of the common package, they are listed in its manifest
+func (g *Generator) writeChanges(name string, files map[string][]byte, packages []string) ([]*fileChange, error) {
+	path := g.packageDir(name)
+	addManifest(files, packages)
+	changes, err := planChanges(path, files)
+	if err != nil {
+		return nil, err
+	}
+	if !g.opts.InPlace {
+		for _, change := range changes {
+			change.dir = name
+		}
+	}
+	if g.opts.Mode == ModeWrite {
+		if err := applyChanges(path, changes); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+// listChanges returns the changes of all packages except kept files, it fails in check mode if the generated code drifted
+func (g *Generator) listChanges(fileChanges []*fileChange) ([]Change, error) {
+	changes := make([]Change, 0)
+	drift := make([]string, 0)
+	for _, change := range fileChanges {
+		if change.op != opKeep {
+			changes = append(changes, Change{Path: change.path(), Op: change.op})
+			drift = append(drift, change.String())
+		}
+	}
+	if g.opts.Mode == ModeCheck && len(drift) != 0 {
+		return changes, fmt.Errorf("generated code in %s is out of date：%s", g.opts.Out, strings.Join(drift, ", "))
+	}
+	return changes, nil
+}
+
+// renderFiles renders all files of the package, the result is keyed by file name
+func (g *Generator) renderFiles(def *APIDefinition, cliImport string) (map[string][]byte, error) {
+	files := make(map[string][]byte, len(def.WebServices)+1)
+
+	//create files for service, they are rendered and formatted concurrently
+	jobs := make([]*renderJob, 0, len(def.WebServices))
+	for _, service := range def.WebServices {
+		service := service
+		jobs = append(jobs, &renderJob{
+			name:   service.fileName(),
+			render: func(w io.Writer) error { return g.renderService(w, service) },
+		})
+	}
+	rendered, err := renderParallel(jobs, g.opts.Workers)
+	if err != nil {
+		return nil, err
+	}
+	for name, content := range rendered {
+		files[name] = content
+	}
+
+	// create helpers built on generated services
+	if data := newCETaskData(def); data != nil {
+		buff := new(bytes.Buffer)
+		if err := g.renderHelper(buff, ceTaskTemplateName, data); err != nil {
+			return nil, err
+		}
+		if err := addFile(files, ceTaskFileName, buff.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	if data := newGateData(def); data != nil {
+		buff := new(bytes.Buffer)
+		if err := g.renderHelper(buff, gateTemplateName, data); err != nil {
+			return nil, err
+		}
+		if err := addFile(files, gateFileName, buff.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+
+	// create main client file, versioned packages wrap the client of the common package
+	buff := new(bytes.Buffer)
+	render := g.renderClient
+	if def.CommonImport != "" {
+		render = g.renderVersionClient
+	}
+	if err := render(buff, def); err != nil {
+		return nil, err
+	}
+	if err := addFile(files, clientFileName, buff.Bytes()); err != nil {
+		return nil, err
+	}
+
+	// create command line tool
+	if cliImport != "" {
+		buff := new(bytes.Buffer)
+		if err := g.renderCLI(buff, &cliData{APIDefinition: def, ImportPath: cliImport}); err != nil {
+			return nil, err
+		}
+		files[cliFileName] = buff.Bytes()
+	}
+
+	// create files of the templates declared by the template dir
+	specs, err := g.loadTemplateSet()
+	if err != nil {
+		return nil, err
+	}
+	if err := g.renderTemplateSet(def, specs, files); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// addFile adds the file rendered besides the service files, a service file of the same name would be overwritten
+func addFile(files map[string][]byte, name string, content []byte) error {
+	if _, ok := files[name]; ok {
+		return fmt.Errorf("generated file %s collides with the file of a service, rename the service", name)
+	}
+	files[name] = content
+	return nil
+}
+
+// renderJob renders a file of the package
+type renderJob struct {
+	name   string
+	render func(w io.Writer) error
+}
+
+// renderParallel runs the jobs with at most workers (the number of CPUs if it's not positive) concurrent renderings,
+// the errors of all failed jobs are returned
+func renderParallel(jobs []*renderJob, workers int) (map[string][]byte, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	results := make([][]byte, len(jobs))
+	errs := make([]error, len(jobs))
+
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers && i < len(jobs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				buff := new(bytes.Buffer)
+				errs[i] = jobs[i].render(buff)
+				results[i] = buff.Bytes()
+			}
+		}()
+	}
+	for i := range jobs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	files := make(map[string][]byte, len(jobs))
+	for i, job := range jobs {
+		files[job.name] = results[i]
+	}
+	return files, errors.Join(errs...)
+}
+
+// planChanges compares rendered files with the content of the path,
+// existing files are updated only if they were generated: start with the marker or are listed in the manifest
+func planChanges(path string, files map[string][]byte) ([]*fileChange, error) {
+	previous, err := readManifest(path)
+	if err != nil {
+		return nil, err
+	}
+	listed := previous.files
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	changes := make([]*fileChange, 0, len(files))
+	for _, name := range names {
+		if isExtFile(name) {
+			return nil, fmt.Errorf("generated file %s would overwrite a hand-written extension file", name)
+		}
+		change := &fileChange{name: name, op: OpCreate, content: files[name]}
+		existing, err := os.ReadFile(filepath.Join(path, name))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			return nil, fmt.Errorf("failed to read file：%w", err)
+		case bytes.Equal(existing, change.content):
+			change.op = opKeep
+		case hasMarker(existing) || listed[name]:
+			change.op = OpUpdate
+		default:
+			return nil, fmt.Errorf("generated file %s would overwrite a file without the generated marker", name)
+		}
+		changes = append(changes, change)
+	}
+
+	stale, err := findStaleFiles(path, files, listed)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range stale {
+		changes = append(changes, &fileChange{name: name, op: OpRemove})
+	}
+	return changes, nil
+}
+
+func applyChanges(path string, changes []*fileChange) error {
+	err := os.MkdirAll(path, targetDirPermission)
+	if err != nil {
+		return fmt.Errorf("cant create destination directory：%w", err)
+	}
+
+	for _, change := range changes {
+		switch change.op {
+		case OpCreate, OpUpdate:
+			if err := writeFile(path, change.name, change.content); err != nil {
+				return err
+			}
+		case OpRemove:
+			if err := os.Remove(filepath.Join(path, change.name)); err != nil {
+				return fmt.Errorf("failed to remove stale file：%w", err)
+			}
+			removeEmptyDirs(path, filepath.Dir(filepath.Join(path, change.name)))
+		}
+	}
+	return nil
+}
+
+// removeEmptyDirs removes the dir and its parents up to the path if they are left empty by removed files,
+// a package dir is removed only when none of its files is generated anymore
+func removeEmptyDirs(path, dir string) {
+	for {
+		if err := os.Remove(dir); err != nil || dir == path {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// writeFile writes the content to a temporary file renamed to the name, so an interrupted run never leaves a partially written file
+func writeFile(path, name string, content []byte) error {
+	target := filepath.Join(path, name)
+	dir := filepath.Dir(target)
+	if err := os.MkdirAll(dir, targetDirPermission); err != nil {
+		return fmt.Errorf("cant create destination directory：%w", err)
+	}
+	file, err := os.CreateTemp(dir, "."+filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create file：%w", err)
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write file：%w", err)
+	}
+	if err := os.Chmod(file.Name(), filePermission); err != nil {
+		return fmt.Errorf("failed to write file：%w", err)
+	}
+	if err := os.Rename(file.Name(), target); err != nil {
+		return fmt.Errorf("failed to write file：%w", err)
+	}
+	return nil
+}
+
+// findStaleFiles returns files left by previous runs of the generator, which are not produced anymore.
+// Files listed in the manifest of the previous run are returned, otherwise only files of the same kinds (extensions)
+// as the generated ones are considered, files without the generated marker and hand-written *_ext.go files are never returned.
+// If nothing is generated, e.g. for a package of a version which isn't targeted anymore, files of every kind are considered.
+func findStaleFiles(path string, generated map[string][]byte, listed map[string]bool) ([]string, error) {
+	exts := make(map[string]bool)
+	for name := range generated {
+		exts[filepath.Ext(name)] = true
+	}
+	stale := make([]string, 0)
+	err := filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			// skip hidden dirs, e.g. .git, and nested generated packages
+			if file != path && (strings.HasPrefix(entry.Name(), ".") || isPackageDir(file)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name, err := filepath.Rel(path, file)
+		if err != nil {
+			return err
+		}
+		name = filepath.ToSlash(name)
+		if _, ok := generated[name]; ok || !entry.Type().IsRegular() || isExtFile(name) {
+			return nil
+		}
+		if listed[name] {
+			stale = append(stale, name)
+			return nil
+		}
+		if len(generated) != 0 && !exts[filepath.Ext(name)] {
+			return nil
+		}
+		owned, err := isGeneratedFile(file)
+		if err != nil {
+			return err
+		}
+		if owned {
+			stale = append(stale, name)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read target dir (%s)：%w", path, err)
+	}
+	return stale, nil
+}
+
+// manifestPackagePrefix starts the lines of the manifest listing version packages of the common package
+const manifestPackagePrefix = "package "
+
+// manifest lists what the previous run generated in addition to the files with the generated marker
+type manifest struct {
+	// files are the generated files without the marker, the manifest included
+	files map[string]bool
+	// packages are the version packages generated with the common package
+	packages []string
+}
+
+// addManifest adds the manifest listing the rendered files without the generated marker
+// and the version packages of the common package, if there are any
+func addManifest(files map[string][]byte, packages []string) {
+	lines := make([]string, 0)
+	for name, content := range files {
+		if !hasMarker(content) {
+			lines = append(lines, name)
+		}
+	}
+	sort.Strings(lines)
+	for _, name := range packages {
+		lines = append(lines, manifestPackagePrefix+name)
+	}
+	if len(lines) == 0 {
+		return
+	}
+	files[manifestFileName] = []byte(manifestMarker + "\n" + strings.Join(lines, "\n") + "\n")
+}
+
+// readManifest returns the manifest written to the path by the previous run, it's empty if there is none
+func readManifest(path string) (*manifest, error) {
+	result := &manifest{files: make(map[string]bool)}
+	content, err := os.ReadFile(filepath.Join(path, manifestFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest：%w", err)
+	}
+	lines := strings.Split(string(content), "\n")
+	if strings.TrimSpace(lines[0]) != manifestMarker {
+		return result, nil
+	}
+	result.files[manifestFileName] = true
+	for _, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, manifestPackagePrefix):
+			// only plain package names are accepted, so a broken manifest can't point outside of the out dir
+			if name := strings.TrimPrefix(line, manifestPackagePrefix); token.IsIdentifier(name) {
+				result.packages = append(result.packages, name)
+			}
+		default:
+			result.files[line] = true
+		}
+	}
+	return result, nil
+}
+
+// hasMarker reports whether the first line of the content is the generated marker
+func hasMarker(content []byte) bool {
+	line, _, _ := bytes.Cut(content, []byte("\n"))
+	line = bytes.TrimSpace(line)
+	return string(line) == generatedMarker || string(line) == markdownMarker
+}
+
+// isPackageDir reports whether the dir contains a generated client package
+func isPackageDir(dir string) bool {
+	for _, name := range []string{clientFileName, commonFileName, coreFileName} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func isGeneratedFile(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to open file (%s)：%w", path, err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		return false, scanner.Err()
+	}
+	line := strings.TrimSpace(scanner.Text())
+	return line == generatedMarker || line == markdownMarker, nil
+}
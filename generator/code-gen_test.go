@@ -0,0 +1,159 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_planChanges(t *testing.T) {
+	dir := t.TempDir()
+	existing := map[string]string{
+		"client.tpl.go": generatedMarker + "\n\npackage p\n",
+		"projects.go":   generatedMarker + "\n\npackage p\n\n// old\n",
+		"removed.go":    generatedMarker + "\n\npackage p\n",
+		"custom.go":     "package p\n",
+		"custom_ext.go": generatedMarker + "\n\npackage p\n",
+		"notes.txt":     generatedMarker + "\n",
+		"mocks/old.go":  generatedMarker + "\n\npackage mocks\n",
+	}
+	if err := os.Mkdir(filepath.Join(dir, "mocks"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range existing {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files := map[string][]byte{
+		"client.tpl.go":   []byte(generatedMarker + "\n\npackage p\n"),
+		"projects.go":     []byte(generatedMarker + "\n\npackage p\n"),
+		"qualitygates.go": []byte(generatedMarker + "\n\npackage p\n"),
+	}
+	changes, err := planChanges(dir, files)
+	if err != nil {
+		t.Fatalf("planChanges() error = %v", err)
+	}
+
+	got := make([]string, 0, len(changes))
+	for _, change := range changes {
+		got = append(got, change.String())
+	}
+	want := []string{"keep client.tpl.go", "update projects.go", "create qualitygates.go", "remove mocks/old.go", "remove removed.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("planChanges() = %v, want %v", got, want)
+	}
+
+	if err := applyChanges(dir, changes); err != nil {
+		t.Fatalf("applyChanges() error = %v", err)
+	}
+	changes, err = planChanges(dir, files)
+	if err != nil {
+		t.Fatalf("planChanges() error = %v", err)
+	}
+	for _, change := range changes {
+		if change.op != opKeep {
+			t.Errorf("planChanges() after applyChanges() = %v, want no changes", change)
+		}
+	}
+	for _, name := range []string{"custom.go", "custom_ext.go"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("applyChanges() removed user file: %v", err)
+		}
+	}
+
+	if _, err := planChanges(dir, map[string][]byte{"custom_ext.go": nil}); err == nil {
+		t.Errorf("planChanges() expected error on overwriting extension file")
+	}
+	if _, err := planChanges(dir, map[string][]byte{"custom.go": []byte(generatedMarker + "\n\npackage p\n")}); err == nil {
+		t.Errorf("planChanges() expected error on overwriting file without the generated marker")
+	}
+}
+
+func Test_writeChanges_manifest(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "custom.yaml"), []byte("custom: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{
+		"client.tpl.go":       []byte(generatedMarker + "\n\npackage p\n"),
+		"config.yaml":         []byte("generated: true\n"),
+		"fixtures/users.json": []byte("[]\n"),
+	}
+	if _, err := NewGenerator(Options{Out: dir, InPlace: true}).writeChanges("p", files, nil); err != nil {
+		t.Fatalf("writeChanges() error = %v", err)
+	}
+	manifest, err := os.ReadFile(filepath.Join(dir, manifestFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := manifestMarker + "\nconfig.yaml\nfixtures/users.json\n"; string(manifest) != want {
+		t.Errorf("writeChanges() manifest = %q, want %q", manifest, want)
+	}
+
+	// files without the marker are updated and removed when they are listed in the manifest
+	changes, err := planChanges(dir, map[string][]byte{"config.yaml": []byte("generated: false\n")})
+	if err != nil || len(changes) == 0 || changes[0].String() != "update config.yaml" {
+		t.Errorf("planChanges() = %v, %v, want update config.yaml", changes, err)
+	}
+	if _, err := planChanges(dir, map[string][]byte{"custom.yaml": []byte("generated: true\n")}); err == nil {
+		t.Errorf("planChanges() expected error on overwriting file missing in the manifest")
+	}
+	files = map[string][]byte{"client.tpl.go": []byte(generatedMarker + "\n\npackage p\n")}
+	changes, err = planChanges(dir, files)
+	if err != nil {
+		t.Fatalf("planChanges() error = %v", err)
+	}
+	got := make([]string, 0, len(changes))
+	for _, change := range changes {
+		got = append(got, change.String())
+	}
+	want := []string{"keep client.tpl.go", "remove " + manifestFileName, "remove config.yaml", "remove fixtures/users.json"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("planChanges() = %v, want %v", got, want)
+	}
+}
+
+func Test_renderParallel(t *testing.T) {
+	jobs := make([]*renderJob, 0)
+	for i := 0; i < 20; i++ {
+		name := fmt.Sprintf("s%d.go", i)
+		jobs = append(jobs, &renderJob{name: name, render: func(w io.Writer) error {
+			if name == "s3.go" || name == "s7.go" {
+				return fmt.Errorf("failed %s", name)
+			}
+			_, err := io.WriteString(w, name)
+			return err
+		}})
+	}
+
+	files, err := renderParallel(jobs, 4)
+	if err == nil || !strings.Contains(err.Error(), "failed s3.go") || !strings.Contains(err.Error(), "failed s7.go") {
+		t.Errorf("renderParallel() error = %v, want errors of all failed jobs", err)
+	}
+	if got := string(files["s12.go"]); got != "s12.go" {
+		t.Errorf("renderParallel() = %q, want %q", got, "s12.go")
+	}
+}
+
+func Test_writeFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := writeFile(dir, "mocks/a.go", []byte("package mocks\n")); err != nil {
+		t.Fatalf("writeFile() error = %v", err)
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, "mocks"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "a.go" {
+		t.Errorf("writeFile() left files %v, want only a.go", entries)
+	}
+	if info, err := entries[0].Info(); err != nil || info.Mode().Perm() != filePermission {
+		t.Errorf("writeFile() mode = %v, %v, want %v", info.Mode(), err, os.FileMode(filePermission))
+	}
+}
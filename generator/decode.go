@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// decodeDefinition decodes the api definition into def.
+// In strict mode unknown fields are errors, otherwise they are ignored and reported as warnings.
+func decodeDefinition(body []byte, def *APIDefinition, strict bool) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(def); err != nil {
+		return nil, err
+	}
+	if strict {
+		return nil, nil
+	}
+
+	var raw interface{}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+	unknown := make(map[string]int)
+	collectUnknownFields(reflect.TypeOf(def), raw, "", unknown)
+
+	paths := make([]string, 0, len(unknown))
+	for path := range unknown {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	warnings := make([]string, 0, len(paths))
+	for _, path := range paths {
+		warnings = append(warnings, fmt.Sprintf("unknown field %s is ignored (%d occurrences)", path, unknown[path]))
+	}
+	return warnings, nil
+}
+
+// collectUnknownFields walks the decoded json along the type t and counts json object keys,
+// which don't match any field of the corresponding struct
+func collectUnknownFields(t reflect.Type, raw interface{}, path string, unknown map[string]int) {
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) || t.Implements(jsonUnmarshalerType) {
+		return
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		collectUnknownFields(t.Elem(), raw, path, unknown)
+	case reflect.Slice:
+		items, ok := raw.([]interface{})
+		if !ok {
+			return
+		}
+		for _, item := range items {
+			collectUnknownFields(t.Elem(), item, path+"[]", unknown)
+		}
+	case reflect.Struct:
+		object, ok := raw.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for key, value := range object {
+			field, ok := jsonField(t, key)
+			if !ok {
+				unknown[strings.TrimPrefix(path+"."+key, ".")]++
+				continue
+			}
+			collectUnknownFields(field.Type, value, strings.TrimPrefix(path+"."+key, "."), unknown)
+		}
+	}
+}
+
+// jsonField finds the struct field, which encoding/json would decode the key into
+func jsonField(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag != "" {
+			if tag == "-" {
+				continue
+			}
+			name = tag
+		}
+		if strings.EqualFold(name, key) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
@@ -0,0 +1,50 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// knownStreamActions are actions returning binary or text content instead of json
+var knownStreamActions = []string{
+	"api/project_badges/measure",
+	"api/project_badges/quality_gate",
+	"api/project_dump/export",
+	"api/qualityprofiles/backup",
+	"api/qualityprofiles/export",
+	"api/system/info",
+}
+
+// ParseStreamActions parses the comma separated list of actions in the api/service/action form
+func ParseStreamActions(str string) ([]string, error) {
+	actions := make([]string, 0)
+	for _, item := range strings.Split(str, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !strings.HasPrefix(item, urlPrefix) || strings.Contains(item, "?") {
+			return nil, fmt.Errorf("invalid stream action %q, it must be in the api/service/action form", item)
+		}
+		actions = append(actions, item)
+	}
+	return actions, nil
+}
+
+// markStreamActions marks the known actions, the extra ones and the actions with non json response examples
+// as streams, their responses are returned as downloads
+func markStreamActions(def *APIDefinition, extra []string) {
+	streams := make(map[string]bool, len(knownStreamActions)+len(extra))
+	for _, key := range knownStreamActions {
+		streams[key] = true
+	}
+	for _, key := range extra {
+		streams[key] = true
+	}
+	for _, service := range def.WebServices {
+		for _, action := range service.Actions {
+			example := action.ResponseExample
+			action.stream = streams[service.Path+"/"+action.Key] || example != nil && example.Format != "json"
+		}
+	}
+}
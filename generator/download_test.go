@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseStreamActions(t *testing.T) {
+	tests := []struct {
+		name    string
+		str     string
+		want    []string
+		wantErr bool
+	}{
+		{name: "should parse an empty list", str: "", want: []string{}},
+		{name: "should parse actions", str: "api/plugins/download, api/system/logs", want: []string{"api/plugins/download", "api/system/logs"}},
+		{name: "should fail on a param", str: "api/system/logs?name", wantErr: true},
+		{name: "should fail on a path without api prefix", str: "system/logs", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStreamActions(tt.str)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseStreamActions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseStreamActions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_markStreamActions(t *testing.T) {
+	backup := &Action{Key: "backup"}
+	search := &Action{Key: "search", ResponseExample: &responseExample{Format: "json"}}
+	logs := &Action{Key: "logs", ResponseExample: &responseExample{Format: "txt"}}
+	download := &Action{Key: "download"}
+	def := &APIDefinition{WebServices: []*WebService{
+		{Path: "api/qualityprofiles", Actions: []*Action{backup, search}},
+		{Path: "api/system", Actions: []*Action{logs}},
+		{Path: "api/plugins", Actions: []*Action{download}},
+	}}
+	markStreamActions(def, []string{"api/plugins/download"})
+
+	got := []bool{backup.IsStream(), search.IsStream(), logs.IsStream(), download.IsStream()}
+	want := []bool{true, false, true, true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("markStreamActions() = %v, want %v", got, want)
+	}
+	if !def.WebServices[0].HasResponses() || def.WebServices[1].HasResponses() {
+		t.Errorf("HasResponses() should be false only for services without json actions")
+	}
+}
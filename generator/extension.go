@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// extFileSuffix is the suffix of hand-written files of the package, the generator never touches them
+	extFileSuffix = "_ext.go"
+	// extTypeSuffix is the suffix of extension types embedded into the generated types, e.g. ProjectsServiceExt
+	extTypeSuffix = "Ext"
+	// extInitMethod is the method of generated types called by their constructors if it's declared
+	extInitMethod = "initExt"
+)
+
+func isExtFile(name string) bool {
+	return strings.HasSuffix(name, extFileSuffix)
+}
+
+// extensions are declarations of hand-written *_ext.go files of the package
+type extensions struct {
+	types   map[string]bool
+	methods map[string]bool
+}
+
+func (e *extensions) hasType(name string) bool {
+	return e != nil && e.types[name]
+}
+
+func (e *extensions) hasMethod(receiver, name string) bool {
+	return e != nil && e.methods[receiver+"."+name]
+}
+
+// loadExtensions parses *_ext.go files of the dir, the dir may not exist yet
+func loadExtensions(dir string) (*extensions, error) {
+	ext := &extensions{types: make(map[string]bool), methods: make(map[string]bool)}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ext, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read target dir (%s)：%w", dir, err)
+	}
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !isExtFile(entry.Name()) {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, entry.Name()), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse extension file：%w", err)
+		}
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if spec, ok := spec.(*ast.TypeSpec); ok {
+						ext.types[spec.Name.Name] = true
+					}
+				}
+			case *ast.FuncDecl:
+				if receiver := receiverName(decl); receiver != "" {
+					ext.methods[receiver+"."+decl.Name.Name] = true
+				}
+			}
+		}
+	}
+	return ext, nil
+}
+
+func receiverName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return ""
+	}
+	expr := decl.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// applyExtensions makes the generated client and services embed extension types
+// and call init methods declared in hand-written files
+func applyExtensions(def *APIDefinition, ext *extensions) {
+	def.ext = ext
+	for _, service := range def.WebServices {
+		service.ext = ext
+	}
+}
@@ -0,0 +1,45 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_loadExtensions(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"projects_ext.go": "package p\n\ntype ProjectsServiceExt struct{}\n\nfunc (s *ProjectsService) initExt() {}\n",
+		"client_ext.go":   "package p\n\nfunc (c Client) initExt() {}\n",
+		"custom.go":       "package p\n\ntype ClientExt struct{}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ext, err := loadExtensions(dir)
+	if err != nil {
+		t.Fatalf("loadExtensions() error = %v", err)
+	}
+	def := &APIDefinition{WebServices: []*WebService{{Path: "api/projects"}, {Path: "api/ce"}}}
+	applyExtensions(def, ext)
+
+	if got := def.WebServices[0].ExtType(); got != "ProjectsServiceExt" {
+		t.Errorf("ExtType() = %v, want ProjectsServiceExt", got)
+	}
+	if !def.WebServices[0].HasExtInit() || def.WebServices[1].HasExtInit() {
+		t.Errorf("HasExtInit() should be true only for the projects service")
+	}
+	if got := def.ClientExtType(); got != "" {
+		t.Errorf("ClientExtType() = %v, types of other files should be ignored", got)
+	}
+	if !def.HasClientExtInit() {
+		t.Errorf("HasClientExtInit() = false, want true")
+	}
+
+	if _, err := loadExtensions(filepath.Join(dir, "missing")); err != nil {
+		t.Errorf("loadExtensions() error = %v on missing dir", err)
+	}
+}
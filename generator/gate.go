@@ -0,0 +1,77 @@
+package generator
+
+const (
+	qualityGatesServicePath    = "api/qualitygates"
+	projectBranchesServicePath = "api/project_branches"
+	gateTemplateName           = "gate.tpl"
+	gateFileName               = "qualitygates_gate.go"
+)
+
+// gateData is passed to the gate template, the helper is built on the project_status action,
+// pending analyses are waited for with the ce task waiter and found with the ce activity action
+type gateData struct {
+	PackageName string
+	Service     *WebService
+	Status      *Action
+	AnalysisID  *Param
+	ProjectKey  *Param
+	Branch      *Param
+	PullRequest *Param
+
+	// CE is nil if the definition doesn't have the ce task waiter
+	CE *ceTaskData
+	// Activity is nil if the pending tasks of a project can't be found
+	Activity          *Action
+	ActivityComponent *Param
+	ActivityStatus    *Param
+	// Branches is nil if the main branch can't be resolved, then only tasks without a branch match the main branch
+	BranchesService *WebService
+	Branches        *Action
+	BranchesProject *Param
+}
+
+// newGateData returns the data of the quality gate helper, or nil if the definition lacks api/qualitygates/project_status
+func newGateData(def *APIDefinition) *gateData {
+	service, status := findAction(def, qualityGatesServicePath, "project_status")
+	if status == nil || status.IsStream() {
+		return nil
+	}
+	data := &gateData{
+		PackageName: def.PackageName,
+		Service:     service,
+		Status:      status,
+		AnalysisID:  stringParam(status, "analysisId"),
+		ProjectKey:  stringParam(status, "projectKey"),
+		Branch:      stringParam(status, "branch"),
+		PullRequest: stringParam(status, "pullRequest"),
+		CE:          newCETaskData(def),
+	}
+	if data.ProjectKey == nil {
+		return nil
+	}
+	if data.CE == nil {
+		return data
+	}
+
+	_, activity := findAction(def, ceServicePath, "activity")
+	if activity == nil || activity.IsStream() {
+		return data
+	}
+	component, taskStatus := stringParam(activity, "component"), findParam(activity, "status")
+	if component != nil && taskStatus != nil && taskStatus.IsList() {
+		data.Activity, data.ActivityComponent, data.ActivityStatus = activity, component, taskStatus
+	}
+	if data.Activity == nil || data.Branch == nil {
+		return data
+	}
+
+	// tasks of the main branch have the branch name on editions supporting branches
+	branchesService, branches := findAction(def, projectBranchesServicePath, "list")
+	if branches == nil || branches.IsStream() {
+		return data
+	}
+	if project := stringParam(branches, "project"); project != nil {
+		data.BranchesService, data.Branches, data.BranchesProject = branchesService, branches, project
+	}
+	return data
+}
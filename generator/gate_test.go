@@ -0,0 +1,152 @@
+package generator
+
+import "testing"
+
+func Test_newGateData(t *testing.T) {
+	status := &Action{Key: "project_status", Params: []*Param{{Key: "analysisId"}, {Key: "projectKey"}, {Key: "branch"}}}
+	task := &Action{Key: "task", Params: []*Param{{Key: "id"}}}
+	activity := &Action{Key: "activity", Params: []*Param{{Key: "component"}, {Key: "status", MaxValuesAllowed: 5}}}
+	def := &APIDefinition{WebServices: []*WebService{
+		{Path: "api/ce", Actions: []*Action{task, activity}},
+		{Path: "api/qualitygates", Actions: []*Action{status}},
+	}}
+
+	got := newGateData(def)
+	if got == nil {
+		t.Fatalf("newGateData() = nil, want data")
+	}
+	if got.ProjectKey == nil || got.AnalysisID == nil || got.Branch == nil || got.PullRequest != nil {
+		t.Errorf("newGateData() params = %v, %v, %v, %v", got.ProjectKey, got.AnalysisID, got.Branch, got.PullRequest)
+	}
+	if got.CE == nil || got.Activity != activity {
+		t.Errorf("newGateData() should wait for pending tasks found with ce activity")
+	}
+
+	branches := &Action{Key: "list", Params: []*Param{{Key: "project"}}}
+	def.WebServices = append(def.WebServices, &WebService{Path: "api/project_branches", Actions: []*Action{branches}})
+	if got := newGateData(def); got.Branches != branches {
+		t.Errorf("newGateData() should resolve the main branch with project_branches list")
+	}
+
+	activity.Params = activity.Params[:1]
+	if got := newGateData(def); got.Activity != nil {
+		t.Errorf("newGateData() should not find pending tasks without status param")
+	}
+
+	status.Params = status.Params[:1]
+	if got := newGateData(def); got != nil {
+		t.Errorf("newGateData() = %v, want nil without projectKey param", got)
+	}
+}
+
+const gateSnapshot = `{"webServices": [
+	{"path": "api/ce", "description": "Get information on Compute Engine tasks.", "actions": [
+		{"key": "task", "description": "Give Compute Engine task details", "since": "5.2", "post": false,
+			"params": [{"key": "id", "description": "Id of task", "required": true}]},
+		{"key": "activity", "description": "Search for tasks", "since": "5.2", "post": false,
+			"params": [{"key": "component", "description": "Key of the component"},
+				{"key": "status", "description": "Comma separated list of task statuses", "maxValuesAllowed": 5}]}]},
+	{"path": "api/project_branches", "description": "Manage branch.", "actions": [
+		{"key": "list", "description": "List the branches of a project", "since": "6.6", "post": false,
+			"params": [{"key": "project", "description": "Project key", "required": true}]}]},
+	{"path": "api/qualitygates", "description": "Manage quality gates.", "actions": [
+		{"key": "project_status", "description": "Get the quality gate status of a project", "since": "5.3", "post": false,
+			"params": [{"key": "analysisId", "description": "Analysis id"}, {"key": "projectKey", "description": "Project key"},
+				{"key": "branch", "description": "Branch key"}, {"key": "pullRequest", "description": "Pull request id"}]}]}]}`
+
+// gateTest runs in the generated package, the server has pending tasks of the main branch named main
+// and of the pull request 5, tasks are done on the second poll, the gate status is OK only for the analysis of a task
+const gateTest = `package sonar
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func serveGate(t *testing.T, tasks string) (*Client, func() []string) {
+	mu := sync.Mutex{}
+	polls := make(map[string]int)
+	var statusQueries []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		query := r.URL.Query()
+		switch r.URL.Path {
+		case "/api/ce/activity":
+			fmt.Fprintf(w, ` + "`" + `{"tasks": [%s]}` + "`" + `, tasks)
+		case "/api/project_branches/list":
+			fmt.Fprint(w, ` + "`" + `{"branches": [{"name": "feature", "isMain": false}, {"name": "main", "isMain": true}]}` + "`" + `)
+		case "/api/ce/task":
+			id := query.Get("id")
+			polls[id]++
+			status := "IN_PROGRESS"
+			if polls[id] > 1 {
+				status = "SUCCESS"
+			}
+			fmt.Fprintf(w, ` + "`" + `{"task": {"id": %q, "status": %q, "analysisId": "analysis-%s"}}` + "`" + `, id, status, id)
+		case "/api/qualitygates/project_status":
+			statusQueries = append(statusQueries, r.URL.RawQuery)
+			status := "ERROR"
+			if query.Get("analysisId") != "" {
+				status = "OK"
+			}
+			fmt.Fprintf(w, ` + "`" + `{"projectStatus": {"status": %q}}` + "`" + `, status)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return NewClient(ts.Client(), ts.URL, "", ""), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return statusQueries
+	}
+}
+
+func TestProjectGate(t *testing.T) {
+	const (
+		mainTask    = ` + "`" + `{"id": "T1", "status": "PENDING"}` + "`" + `
+		namedMain   = ` + "`" + `{"id": "T2", "status": "IN_PROGRESS", "branch": "main", "branchType": "BRANCH"}` + "`" + `
+		feature     = ` + "`" + `{"id": "T3", "status": "PENDING", "branch": "feature", "branchType": "BRANCH"}` + "`" + `
+		pullRequest = ` + "`" + `{"id": "T4", "status": "PENDING", "pullRequest": "5", "branchType": "PULL_REQUEST"}` + "`" + `
+	)
+	tests := []struct {
+		name  string
+		tasks string
+		opts  *GateOptions
+		want  string
+	}{
+		{name: "should wait for the pending task", tasks: mainTask, opts: &GateOptions{}, want: "analysisId=analysis-T1"},
+		{name: "should wait for the task of the named main branch", tasks: feature + "," + pullRequest + "," + namedMain, opts: &GateOptions{}, want: "analysisId=analysis-T2"},
+		{name: "should wait for the task of the branch", tasks: namedMain + "," + feature, opts: &GateOptions{Branch: "feature"}, want: "analysisId=analysis-T3"},
+		{name: "should wait for the task of the pull request", tasks: namedMain + "," + pullRequest, opts: &GateOptions{PullRequest: "5"}, want: "analysisId=analysis-T4"},
+		{name: "should read the status without pending task", tasks: feature + "," + pullRequest, opts: &GateOptions{}, want: "projectKey=p"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, statusQueries := serveGate(t, tt.tasks)
+			tt.opts.ProjectKey = "p"
+			tt.opts.Wait = &WaitOptions{Interval: time.Millisecond, Timeout: 5 * time.Second}
+			status, err := client.Qualitygates().ProjectGate(context.Background(), tt.opts)
+			if err != nil {
+				t.Fatalf("ProjectGate() error = %v", err)
+			}
+			if got := statusQueries(); len(got) != 1 || got[0] != tt.want {
+				t.Errorf("ProjectGate() queried status with %v, want %s", got, tt.want)
+			}
+			if tt.want != "projectKey=p" && (status.Status != GateOK || !status.Passed) {
+				t.Errorf("ProjectGate() = %s, want passed", status.Summary())
+			}
+		})
+	}
+}
+`
+
+func Test_ProjectGate(t *testing.T) {
+	testGenerated(t, Options{}, gateSnapshot, gateTest)
+}
@@ -0,0 +1,233 @@
+// Package generator generates SonarQube web-api clients, OpenAPI documents and api references
+// from the api definition of a server (api/webservices/list) or its snapshot.
+//
+// The definition is loaded with Generator.Load, can be narrowed with Filter and is generated with Generator.Generate:
+//
+//	g := generator.NewGenerator(generator.Options{Host: "http://localhost:9000", Out: "internal"})
+//	def, err := g.Load(&generator.Target{})
+//	if err != nil {
+//		return err
+//	}
+//	generator.Filter(def, func(a *generator.Action) bool {
+//		return strings.HasPrefix(a.Path(), "api/projects/")
+//	})
+//	_, err = g.Generate(def)
+//	return err
+package generator
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"path"
+	"sync"
+	"text/template"
+)
+
+// Options configures a Generator, the zero value of a field means its default unless documented otherwise
+type Options struct {
+	// HTTPClient sends requests to the server, default client has the default timeout
+	HTTPClient *http.Client
+	// Host is the SonarQube server, e.g. http://localhost:9000
+	Host string
+	// Auth is the Authorization header value sent to the server
+	Auth string
+	// Deprecated and Internal include deprecated and internal api in the definition
+	Deprecated bool
+	Internal   bool
+	// Strict fails on unknown fields of the api definition
+	Strict bool
+	// Examples loads response examples
+	Examples bool
+	// PackageName is the name of the generated package, default sonarqube_client
+	PackageName string
+	// Renames maps api paths to Go names, see LoadRenames
+	Renames map[string]string
+	// FileParams are params uploaded as files in addition to the known ones, see ParseFileParams
+	FileParams []string
+	// StreamActions are actions returning binary or text content in addition to the known ones, see ParseStreamActions
+	StreamActions []string
+
+	// Out is the output directory, default the current one
+	Out string
+	// InPlace generates the package into Out itself instead of its subdirectory named after the package,
+	// e.g. into the package running go generate
+	InPlace bool
+	// Format is the output format, default go
+	Format string
+	// Mode defines what is done with the generated files, default they are written
+	Mode Mode
+	// TemplateDir is the template directory, default the built-in templates
+	TemplateDir string
+	// Funcs are additional functions of templates, they override the built-in ones with the same names
+	Funcs template.FuncMap
+	// DocWidth is the width of generated doc comments, 0 disables wrapping
+	DocWidth int
+	// Workers is the number of files rendered concurrently, default the number of CPUs
+	Workers int
+	// CLI generates the command line tool, CLIImport is the import path of the generated package used by it
+	// (default: resolved from go.mod)
+	CLI       bool
+	CLIImport string
+	// GeneratorVersion is written to the header of generated files, default the version of the module
+	GeneratorVersion string
+}
+
+// Generator loads api definitions and generates code from them, it keeps no global state,
+// so generators with different options can be used concurrently
+type Generator struct {
+	opts      Options
+	helpers   template.FuncMap
+	templates fs.FS
+
+	// parsed templates are shared by all renderings, templates can be executed concurrently
+	parsedMu sync.Mutex
+	parsed   map[string]*template.Template
+}
+
+func NewGenerator(opts Options) *Generator {
+	if opts.Out == "" {
+		opts.Out = "."
+	}
+	if opts.Format == "" {
+		opts.Format = FormatGo
+	}
+	if opts.GeneratorVersion == "" {
+		opts.GeneratorVersion = buildVersion()
+	}
+	helpers := newTemplateHelpers(opts.DocWidth)
+	for name, f := range opts.Funcs {
+		helpers[name] = f
+	}
+	return &Generator{
+		opts:      opts,
+		helpers:   helpers,
+		templates: templateFS(opts.TemplateDir),
+		parsed:    make(map[string]*template.Template),
+	}
+}
+
+// Load loads the definition of the target, api which isn't included by the options or isn't available
+// in the target version is filtered out
+func (g *Generator) Load(t *Target) (*APIDefinition, error) {
+	def, err := loadAPI(g.opts.HTTPClient, g.opts.Host, g.opts.Deprecated, g.opts.Internal, g.opts.Strict, g.opts.Examples, t.Version, t.Snapshot, g.opts.Auth)
+	if err != nil {
+		return nil, err
+	}
+	if g.opts.PackageName != "" {
+		def.setPackageName(g.opts.PackageName)
+	}
+	markFileParams(def, g.opts.FileParams)
+	markStreamActions(def, g.opts.StreamActions)
+	return def, nil
+}
+
+// Filter keeps the actions of the definition for which keep returns true,
+// services left without actions are removed
+func Filter(def *APIDefinition, keep func(a *Action) bool) {
+	services := make([]*WebService, 0, len(def.WebServices))
+	for _, service := range def.WebServices {
+		actions := make([]*Action, 0, len(service.Actions))
+		for _, action := range service.Actions {
+			if keep(action) {
+				actions = append(actions, action)
+			}
+		}
+		if len(actions) == 0 && len(service.Actions) != 0 {
+			continue
+		}
+		service.Actions = actions
+		services = append(services, service)
+	}
+	def.WebServices = services
+}
+
+// Run loads the definitions of the targets and generates them, the changed files are returned
+func (g *Generator) Run(targets []*Target) ([]Change, error) {
+	defs := make([]*APIDefinition, 0, len(targets))
+	for _, t := range targets {
+		def, err := g.Load(t)
+		if err != nil {
+			return nil, err
+		}
+		defs = append(defs, def)
+	}
+	return g.Generate(defs...)
+}
+
+// Generate generates the code of the definitions loaded by Load, warnings of loading and naming are logged.
+// Several definitions of different versions are generated into a package per version sharing the common package.
+// The created, updated and removed files are returned, in a dry run or a check they are only planned.
+func (g *Generator) Generate(defs ...*APIDefinition) ([]Change, error) {
+	if len(defs) == 0 {
+		return nil, fmt.Errorf("no api definitions to generate")
+	}
+	for _, def := range defs {
+		nameDefinition(def, g.opts.Renames)
+		for _, warning := range def.warnings {
+			if len(defs) > 1 {
+				warning = def.Version.String() + ": " + warning
+			}
+			log.Printf("warning: %s", warning)
+		}
+	}
+
+	if len(defs) == 1 {
+		def := defs[0]
+		importPath := ""
+		if g.opts.CLI && g.opts.Format == FormatGo {
+			importPath = g.opts.CLIImport
+			if importPath == "" {
+				var err error
+				if importPath, err = detectImportPath(g.packageDir(def.PackageName)); err != nil {
+					return nil, fmt.Errorf("%w：%w", ErrCLIImport, err)
+				}
+			}
+		}
+		changes, err := g.generateCode(def, importPath)
+		if err != nil {
+			return nil, err
+		}
+		return g.listChanges(changes)
+	}
+
+	// every version is generated into its own package, go packages share the common package
+	if g.opts.InPlace {
+		return nil, fmt.Errorf("multiple target versions can't be generated in place")
+	}
+	commonName := defs[0].PackageName
+	commonImport := ""
+	if g.opts.Format == FormatGo {
+		if g.opts.CLIImport != "" {
+			return nil, fmt.Errorf("import path of the command line tool can't be set with multiple target versions")
+		}
+		var err error
+		if commonImport, err = detectImportPath(g.opts.Out + "/" + commonName); err != nil {
+			return nil, err
+		}
+	}
+	if err := setVersionPackages(defs, commonName, commonImport); err != nil {
+		return nil, err
+	}
+	changes := make([]*fileChange, 0)
+	for _, def := range defs {
+		importPath := ""
+		if g.opts.CLI && g.opts.Format == FormatGo {
+			importPath = path.Dir(commonImport) + "/" + def.PackageName
+		}
+		packageChanges, err := g.generateCode(def, importPath)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, packageChanges...)
+	}
+	if g.opts.Format == FormatGo {
+		packageChanges, err := g.generateCommon(commonName, defs)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, packageChanges...)
+	}
+	return g.listChanges(changes)
+}
@@ -0,0 +1,300 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"text/template"
+)
+
+const generatorSnapshot = `{"webServices": [{"path": "api/projects", "since": "2.10", "description": "Manage project existence.",
+	"actions": [{"key": "search", "description": "Search projects", "since": "6.3", "post": false,
+		"params": [{"key": "q", "description": "Limit search", "required": false}]}]}]}`
+
+const (
+	generatedGoMod = "module generated\n\ngo 1.20\n\nrequire github.com/pkg/errors v0.9.1\n"
+	generatedGoSum = `github.com/pkg/errors v0.9.1 h1:FEBLx1zS214owpjy7qsBeixbURkuhQAwrK5UwLGTwt4=
+github.com/pkg/errors v0.9.1/go.mod h1:bwawxfHBFNV+L2hUp1rHADufV3IMtnDRdf1r5NINEl0=
+`
+)
+
+// testGenerated generates package sonar from the snapshot into a temporary module and runs the test source in it,
+// the go command and github.com/pkg/errors from the module cache or the proxy are needed
+func testGenerated(t *testing.T, opts Options, snapshot, test string) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skip running generated code in short mode")
+	}
+	goCmd, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command is not found")
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"snapshot.json":       snapshot,
+		"go.mod":              generatedGoMod,
+		"go.sum":              generatedGoSum,
+		"sonar/sonar_test.go": test,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opts.Out, opts.PackageName, opts.DocWidth = dir, "sonar", DefaultDocWidth
+	if _, err := NewGenerator(opts).Run([]*Target{{Version: "7.1", Snapshot: filepath.Join(dir, "snapshot.json")}}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	cmd := exec.Command(goCmd, "test", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go test of generated code error = %v\n%s", err, out)
+	}
+}
+
+func Test_Generator_Run(t *testing.T) {
+	snapshot := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := os.WriteFile(snapshot, []byte(generatorSnapshot), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// generators keep no global state, so they can run concurrently with different options
+	names := []string{"first", "second"}
+	outs := make([]string, len(names))
+	errs := make([]error, len(names))
+	wg := sync.WaitGroup{}
+	for i, name := range names {
+		outs[i] = t.TempDir()
+		g := NewGenerator(Options{PackageName: name, Out: outs[i], DocWidth: DefaultDocWidth})
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = g.Run([]*Target{{Version: "7.1", Snapshot: snapshot}})
+		}(i)
+	}
+	wg.Wait()
+
+	for i, name := range names {
+		if errs[i] != nil {
+			t.Fatalf("Run() error = %v", errs[i])
+		}
+		src, err := os.ReadFile(filepath.Join(outs[i], name, "projects.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(src), "package "+name+"\n") {
+			t.Errorf("Run() generated %s/projects.go without package %s", outs[i], name)
+		}
+	}
+}
+
+func Test_Generator_Run_check(t *testing.T) {
+	snapshot := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := os.WriteFile(snapshot, []byte(generatorSnapshot), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+	if err := os.WriteFile(filepath.Join(out, "go.mod"), []byte("module example.com/out\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{PackageName: "sonar", Out: out}
+	targets := []*Target{{Version: "7.1", Snapshot: snapshot}, {Version: "6.7", Snapshot: snapshot}}
+	if _, err := NewGenerator(opts).Run(targets); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// the drift of every package is reported by its path relative to the out directory
+	for _, name := range []string{"sonar71/projects.go", "sonar67/projects.go"} {
+		if err := os.WriteFile(filepath.Join(out, name), []byte(generatedMarker+"\n\npackage edited\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	opts.Mode = ModeDryRun
+	changes, err := NewGenerator(opts).Run(targets)
+	want := []Change{{Path: "sonar71/projects.go", Op: OpUpdate}, {Path: "sonar67/projects.go", Op: OpUpdate}}
+	if err != nil || !reflect.DeepEqual(changes, want) {
+		t.Errorf("Run() dry run = %v, %v, want %v", changes, err, want)
+	}
+	opts.Mode = ModeCheck
+	_, err = NewGenerator(opts).Run(targets)
+	if err == nil || !strings.Contains(err.Error(), "update sonar71/projects.go, update sonar67/projects.go") {
+		t.Errorf("Run() check error = %v, want drift of both packages", err)
+	}
+
+	// the common package named like a version package would remove the files of the version package
+	opts = Options{PackageName: "sonar71", Out: out}
+	if _, err := NewGenerator(opts).Run(targets); err == nil {
+		t.Errorf("Run() expected error on the common package named like a version package")
+	}
+	if _, err := os.Stat(filepath.Join(out, "sonar71", "projects.go")); err != nil {
+		t.Errorf("Run() removed a file of the version package: %v", err)
+	}
+}
+
+func Test_Generator_Run_removedTarget(t *testing.T) {
+	snapshot := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := os.WriteFile(snapshot, []byte(generatorSnapshot), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+	if err := os.WriteFile(filepath.Join(out, "go.mod"), []byte("module example.com/out\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{PackageName: "sonar", Out: out}
+	targets := []*Target{{Version: "7.1", Snapshot: snapshot}, {Version: "6.7", Snapshot: snapshot}, {Version: "6.8", Snapshot: snapshot}}
+	if _, err := NewGenerator(opts).Run(targets); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// the package of the dropped version is reported as drift
+	targets = targets[:2]
+	opts.Mode = ModeCheck
+	_, err := NewGenerator(opts).Run(targets)
+	if err == nil || !strings.Contains(err.Error(), "remove sonar68/projects.go") {
+		t.Errorf("Run() check error = %v, want removal of sonar68", err)
+	}
+
+	opts.Mode = ModeWrite
+	if _, err := NewGenerator(opts).Run(targets); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(out, "sonar68")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Run() left the package of the dropped version: %v", err)
+	}
+	opts.Mode = ModeCheck
+	if _, err := NewGenerator(opts).Run(targets); err != nil {
+		t.Errorf("Run() check error = %v", err)
+	}
+}
+
+func Test_Generator_Run_cliImport(t *testing.T) {
+	snapshot := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := os.WriteFile(snapshot, []byte(generatorSnapshot), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// the out directory isn't in a module, so the import path can't be detected
+	_, err := NewGenerator(Options{PackageName: "sonar", Out: t.TempDir(), CLI: true}).Run([]*Target{{Version: "7.1", Snapshot: snapshot}})
+	if !errors.Is(err, ErrCLIImport) {
+		t.Errorf("Run() error = %v, want %v", err, ErrCLIImport)
+	}
+
+	// other formats don't generate the command line tool, so the import path isn't needed
+	opts := Options{PackageName: "sonar", Out: t.TempDir(), CLI: true, Format: FormatOpenAPI}
+	if _, err := NewGenerator(opts).Run([]*Target{{Version: "7.1", Snapshot: snapshot}}); err != nil {
+		t.Errorf("Run() openapi error = %v", err)
+	}
+}
+
+func Test_Filter(t *testing.T) {
+	projects := &WebService{Path: "api/projects", Actions: []*Action{{Key: "search"}, {Key: "delete"}}}
+	users := &WebService{Path: "api/users", Actions: []*Action{{Key: "search"}}}
+	empty := &WebService{Path: "api/empty"}
+	def := &APIDefinition{WebServices: []*WebService{projects, users, empty}}
+
+	Filter(def, func(a *Action) bool {
+		return a.Key == "delete"
+	})
+
+	if !reflect.DeepEqual(def.WebServices, []*WebService{projects, empty}) {
+		t.Errorf("Filter() services = %v, want api/projects and api/empty", def.WebServices)
+	}
+	if len(projects.Actions) != 1 || projects.Actions[0].Key != "delete" {
+		t.Errorf("Filter() actions = %v, want delete", projects.Actions)
+	}
+}
+
+func Test_NewGenerator_Funcs(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "test.tpl"), []byte(`{{shout .}} {{upper .}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	g := NewGenerator(Options{TemplateDir: dir, Funcs: template.FuncMap{
+		"shout": func(s string) string { return s + "!" },
+		"upper": func(s string) string { return "overridden" },
+	}})
+	tpl, err := g.parseTemplate("test.tpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := new(strings.Builder)
+	if err := tpl.Execute(result, "hi"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "hi! overridden"; result.String() != want {
+		t.Errorf("got %q, want %q", result.String(), want)
+	}
+}
+
+func Test_Generator_Run_inPlace(t *testing.T) {
+	snapshot := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := os.WriteFile(snapshot, []byte(generatorSnapshot), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+	// a nested generated package must not be treated as stale files of the package
+	nested := filepath.Join(out, "nested")
+	if err := os.Mkdir(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{clientFileName, "projects.go"} {
+		if err := os.WriteFile(filepath.Join(nested, name), []byte(generatedMarker+"\n\npackage nested\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opts := Options{PackageName: "sonar", Out: out, InPlace: true, GeneratorVersion: "v1.2.3"}
+	targets := []*Target{{Version: "7.1", Snapshot: snapshot}}
+	if _, err := NewGenerator(opts).Run(targets); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	src, err := os.ReadFile(filepath.Join(out, "projects.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stamp := "// sonarqube-api-client-gen v1.2.3, SonarQube 7.1, definition sha256:" + definitionHash([]byte(generatorSnapshot))[:hashLength] + "\n"
+	if want := generatedMarker + "\n" + stamp; !strings.HasPrefix(string(src), want) {
+		t.Errorf("Run() header = %q, want %q", strings.SplitN(string(src), "\n\n", 2)[0], want)
+	}
+	if _, err := os.Stat(filepath.Join(nested, "projects.go")); err != nil {
+		t.Errorf("Run() removed a file of the nested package: %v", err)
+	}
+
+	// regeneration is deterministic
+	opts.Mode = ModeCheck
+	if _, err := NewGenerator(opts).Run(targets); err != nil {
+		t.Errorf("Run() check error = %v", err)
+	}
+
+	// a hand-written file of the package is never overwritten
+	opts.Mode = ModeWrite
+	handWritten := []byte("package sonar\n\n// Projects is written by hand\n")
+	if err := os.WriteFile(filepath.Join(out, "projects.go"), handWritten, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewGenerator(opts).Run(targets); err == nil {
+		t.Errorf("Run() expected error on overwriting a hand-written file")
+	}
+	if src, _ := os.ReadFile(filepath.Join(out, "projects.go")); !bytes.Equal(src, handWritten) {
+		t.Errorf("Run() overwrote the hand-written file")
+	}
+
+	targets = append(targets, &Target{Version: "6.7", Snapshot: snapshot})
+	if _, err := NewGenerator(opts).Run(targets); err == nil {
+		t.Errorf("Run() expected error on generating several versions in place")
+	}
+}
@@ -0,0 +1,415 @@
+package generator
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultDocWidth is the default width of generated doc comments, 0 disables wrapping
+const DefaultDocWidth = 100
+
+var attrRE = regexp.MustCompile(`(?is)([a-z-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+
+type htmlTokenKind int
+
+const (
+	htmlText htmlTokenKind = iota
+	htmlStartTag
+	htmlEndTag
+)
+
+type htmlToken struct {
+	kind  htmlTokenKind
+	name  string
+	text  string
+	attrs map[string]string
+}
+
+// tokenizeHTML splits the html of SonarQube descriptions into text and tags,
+// text is unescaped, malformed tags are treated as text
+func tokenizeHTML(str string) []*htmlToken {
+	tokens := make([]*htmlToken, 0)
+	text := func(s string) {
+		if s != "" {
+			tokens = append(tokens, &htmlToken{kind: htmlText, text: html.UnescapeString(s)})
+		}
+	}
+	for {
+		start := strings.IndexByte(str, '<')
+		if start < 0 {
+			text(str)
+			return tokens
+		}
+		end := strings.IndexByte(str[start:], '>')
+		tag := ""
+		if end > 0 {
+			tag = str[start+1 : start+end]
+		}
+		name := ""
+		if fields := strings.Fields(strings.TrimPrefix(tag, "/")); len(fields) != 0 {
+			name = strings.ToLower(strings.TrimRight(fields[0], "/"))
+		}
+		if end < 0 || name == "" || !isTagName(name) {
+			text(str[:start+1])
+			str = str[start+1:]
+			continue
+		}
+		text(str[:start])
+		token := &htmlToken{kind: htmlStartTag, name: name}
+		if strings.HasPrefix(tag, "/") {
+			token.kind = htmlEndTag
+		} else {
+			token.attrs = make(map[string]string)
+			for _, attr := range attrRE.FindAllStringSubmatch(tag, -1) {
+				token.attrs[strings.ToLower(attr[1])] = html.UnescapeString(attr[2] + attr[3] + attr[4])
+			}
+		}
+		tokens = append(tokens, token)
+		str = str[start+end+1:]
+	}
+}
+
+func isTagName(name string) bool {
+	for i, r := range name {
+		if !(r >= 'a' && r <= 'z' || i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// docRenderer converts html tokens to go doc comment blocks
+type docRenderer struct {
+	width  int
+	blocks [][]string
+	// line is the current (unfinished) line of the current block
+	line    strings.Builder
+	current []string
+	// lists is a stack of open lists, a counter of items for ordered lists and -1 for unordered ones
+	lists  []int
+	indent string
+	pre    bool
+	links  []string
+	// link is the href of the currently open link
+	link     string
+	linkText strings.Builder
+}
+
+// htmlToDoc converts html of SonarQube descriptions to the text of a go doc comment:
+// paragraphs, lists and code blocks are separated with empty lines, links become doc links,
+// text is wrapped at width (0 disables wrapping)
+func htmlToDoc(str string, width int) []string {
+	r := &docRenderer{width: width}
+	for _, token := range tokenizeHTML(str) {
+		r.token(token)
+	}
+	r.endBlock()
+	if len(r.links) != 0 {
+		r.blocks = append(r.blocks, r.links)
+	}
+
+	lines := make([]string, 0)
+	for i, block := range r.blocks {
+		if i != 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, block...)
+	}
+	return lines
+}
+
+func (r *docRenderer) token(t *htmlToken) {
+	switch t.kind {
+	case htmlText:
+		r.text(t.text)
+	case htmlStartTag:
+		r.startTag(t)
+	case htmlEndTag:
+		r.endTag(t.name)
+	}
+}
+
+func (r *docRenderer) text(text string) {
+	if r.link != "" {
+		r.linkText.WriteString(text)
+		return
+	}
+	if r.pre {
+		for i, line := range strings.Split(text, "\n") {
+			if i != 0 {
+				r.breakLine()
+			}
+			r.line.WriteString(line)
+		}
+		return
+	}
+	for i, line := range strings.Split(text, "\n") {
+		if i != 0 {
+			r.breakLine()
+		}
+		r.write(line)
+	}
+}
+
+// write appends text to the current line collapsing whitespaces
+func (r *docRenderer) write(text string) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		if text != "" && r.line.Len() != 0 {
+			r.line.WriteString(" ")
+		}
+		return
+	}
+	if r.line.Len() != 0 && strings.IndexFunc(text[:1], isSpace) == 0 {
+		r.line.WriteString(" ")
+	}
+	r.line.WriteString(strings.Join(fields, " "))
+	if strings.IndexFunc(text[len(text)-1:], isSpace) == 0 {
+		r.line.WriteString(" ")
+	}
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
+
+func (r *docRenderer) startTag(t *htmlToken) {
+	switch t.name {
+	case "br":
+		r.breakLine()
+	case "p", "div", "h1", "h2", "h3", "h4", "h5", "h6", "table", "tr":
+		r.endBlock()
+	case "ul":
+		r.startList(-1)
+	case "ol":
+		r.startList(0)
+	case "li":
+		r.breakLine()
+		bullet := "-"
+		if n := len(r.lists); n != 0 && r.lists[n-1] >= 0 {
+			r.lists[n-1]++
+			bullet = strconv.Itoa(r.lists[n-1]) + "."
+		}
+		r.line.WriteString(bullet + " ")
+	case "pre":
+		r.endBlock()
+		r.pre = true
+	case "code", "tt", "kbd", "samp":
+		if !r.pre {
+			r.write("`")
+		}
+	case "a":
+		if href := t.attrs["href"]; href != "" {
+			r.link = href
+			r.linkText.Reset()
+		}
+	case "td", "th":
+		r.write(" ")
+	}
+}
+
+func (r *docRenderer) endTag(name string) {
+	switch name {
+	case "p", "div", "h1", "h2", "h3", "h4", "h5", "h6", "table", "tr":
+		r.endBlock()
+	case "ul", "ol":
+		r.endList()
+	case "li":
+		r.breakLine()
+	case "pre":
+		r.endBlock()
+		r.pre = false
+	case "code", "tt", "kbd", "samp":
+		if !r.pre {
+			r.line.WriteString("`")
+		}
+	case "a":
+		r.endLink()
+	}
+}
+
+func (r *docRenderer) endLink() {
+	if r.link == "" {
+		return
+	}
+	href, text := r.link, strings.Join(strings.Fields(r.linkText.String()), " ")
+	r.link = ""
+	switch {
+	case text == "" || text == href:
+		r.write(" " + href + " ")
+	case strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "https://"):
+		text = strings.NewReplacer("[", "(", "]", ")").Replace(text)
+		r.write("[" + text + "]")
+		def := "[" + text + "]: " + href
+		for _, link := range r.links {
+			if link == def {
+				return
+			}
+		}
+		r.links = append(r.links, def)
+	default:
+		// relative links point to the server, they can't be resolved
+		r.write(text)
+	}
+}
+
+func (r *docRenderer) startList(counter int) {
+	if len(r.lists) == 0 {
+		r.endBlock()
+	} else {
+		r.breakLine()
+	}
+	r.lists = append(r.lists, counter)
+	r.indent = strings.Repeat("  ", len(r.lists))
+}
+
+func (r *docRenderer) endList() {
+	if len(r.lists) == 0 {
+		return
+	}
+	r.lists = r.lists[:len(r.lists)-1]
+	r.indent = strings.Repeat("  ", len(r.lists))
+	if len(r.lists) == 0 {
+		r.endBlock()
+	} else {
+		r.breakLine()
+	}
+}
+
+// breakLine finishes the current line of the block
+func (r *docRenderer) breakLine() {
+	if r.pre {
+		r.current = append(r.current, "\t"+strings.TrimRight(r.line.String(), " \t\r"))
+		r.line.Reset()
+		return
+	}
+	line := strings.TrimSpace(r.line.String())
+	r.line.Reset()
+	if line == "" {
+		return
+	}
+	if len(r.lists) == 0 {
+		r.current = append(r.current, wrapText(line, "", "", r.width)...)
+		return
+	}
+	// list items continue with the indentation of the text after the bullet
+	first := r.indent
+	next := r.indent
+	if bullet := strings.IndexByte(line, ' '); bullet > 0 && isBullet(line[:bullet]) {
+		next += strings.Repeat(" ", bullet+1)
+	} else {
+		first = next + "  "
+		next = first
+	}
+	r.current = append(r.current, wrapText(line, first, next, r.width)...)
+}
+
+func isBullet(s string) bool {
+	if s == "-" {
+		return true
+	}
+	_, err := strconv.Atoi(strings.TrimSuffix(s, "."))
+	return strings.HasSuffix(s, ".") && err == nil
+}
+
+// endBlock finishes the current block, blocks are separated with empty lines
+func (r *docRenderer) endBlock() {
+	r.endLink()
+	r.breakLine()
+	if r.pre {
+		// trim empty lines around code
+		for len(r.current) != 0 && strings.TrimSpace(r.current[0]) == "" {
+			r.current = r.current[1:]
+		}
+		for len(r.current) != 0 && strings.TrimSpace(r.current[len(r.current)-1]) == "" {
+			r.current = r.current[:len(r.current)-1]
+		}
+	}
+	if len(r.current) != 0 {
+		r.blocks = append(r.blocks, r.current)
+	}
+	r.current = nil
+}
+
+// wrapText wraps the text at width taking "// " prefix of comments into account,
+// first and next are prefixes of the first and next lines, words longer than width aren't split
+func wrapText(text, first, next string, width int) []string {
+	if width <= 0 {
+		return []string{first + text}
+	}
+	lines := make([]string, 0, 1)
+	line := first
+	empty := true
+	for _, word := range strings.Fields(text) {
+		if !empty && len(line)+1+len(word)+3 > width {
+			lines = append(lines, line)
+			line, empty = next, true
+		}
+		if !empty {
+			line += " "
+		}
+		line += word
+		empty = false
+	}
+	return append(lines, line)
+}
+
+// formatDoc converts html to a doc comment, the result is expected to follow a "// " prefix.
+// Link definitions are the last block of the result, moveDocLinks moves them to the end of the whole comment.
+func formatDoc(str string, width int) string {
+	lines := htmlToDoc(str, width)
+	result := new(strings.Builder)
+	for i, line := range lines {
+		if i != 0 {
+			result.WriteString("\n//")
+			if line != "" {
+				result.WriteString(" ")
+			}
+		}
+		result.WriteString(line)
+	}
+	return result.String()
+}
+
+var docLinkRE = regexp.MustCompile(`^//\s*\[[^\]]+\]:\s+\S+$`)
+
+// moveDocLinks moves link definitions to the end of their comments, so they form a separate block,
+// the empty lines left in place of them are removed
+func moveDocLinks(src []byte) []byte {
+	lines := strings.Split(string(src), "\n")
+	result := make([]string, 0, len(lines))
+	links := make([]string, 0)
+	indent := ""
+	flush := func() {
+		if len(links) == 0 {
+			return
+		}
+		for len(result) != 0 && strings.TrimSpace(result[len(result)-1]) == "//" {
+			result = result[:len(result)-1]
+		}
+		result = append(result, indent+"//")
+		result = append(result, links...)
+		links = links[:0]
+	}
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "//") {
+			flush()
+			result = append(result, line)
+			continue
+		}
+		if docLinkRE.MatchString(trimmed) {
+			indent = line[:strings.Index(line, "//")]
+			links = append(links, line)
+			for len(result) != 0 && strings.TrimSpace(result[len(result)-1]) == "//" {
+				result = result[:len(result)-1]
+			}
+			continue
+		}
+		result = append(result, line)
+	}
+	flush()
+	return []byte(strings.Join(result, "\n"))
+}
@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_htmlToDoc(t *testing.T) {
+	tests := []struct {
+		name  string
+		html  string
+		width int
+		want  []string
+	}{
+		{
+			name: "should keep plain text",
+			html: "Search for projects",
+			want: []string{"Search for projects"},
+		},
+		{
+			name: "should break lines and split paragraphs",
+			html: "Requires one of the following permissions:<br/>'Administer'<p>Since 6.3 the <strong>key</strong> is optional</p>",
+			want: []string{"Requires one of the following permissions:", "'Administer'", "", "Since 6.3 the key is optional"},
+		},
+		{
+			name: "should render lists, nested and numbered ones",
+			html: "Steps:<ol><li>one<ul><li>a</li><li>b</li></ul></li><li>two</li></ol>Done",
+			want: []string{"Steps:", "", "  1. one", "    - a", "    - b", "  2. two", "", "Done"},
+		},
+		{
+			name: "should unescape entities and keep code spans",
+			html: "Use <code>&lt;key&gt;</code> &amp; more",
+			want: []string{"Use `<key>` & more"},
+		},
+		{
+			name: "should render links as doc links",
+			html: `See <a href="https://docs.sonarqube.org/latest">the docs</a> or <a href="/api/webservices">web services</a>`,
+			want: []string{"See [the docs] or web services", "", "[the docs]: https://docs.sonarqube.org/latest"},
+		},
+		{
+			name: "should render code blocks",
+			html: "Example:<pre>\n{\n  \"key\": 1\n}\n</pre>",
+			want: []string{"Example:", "", "\t{", "\t  \"key\": 1", "\t}"},
+		},
+		{
+			name: "should keep malformed tags as text",
+			html: "a < b and c <= d",
+			want: []string{"a < b and c <= d"},
+		},
+		{
+			name:  "should wrap text at width",
+			html:  "one two three four five<ul><li>six seven eight</li></ul>",
+			width: 16,
+			want:  []string{"one two three", "four five", "", "  - six seven", "    eight"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := htmlToDoc(tt.html, tt.width); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("htmlToDoc() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_moveDocLinks(t *testing.T) {
+	src := "// Search see [docs]\n//\n// [docs]: https://docs.sonarqube.org\n// Since 6.3\nfunc Search() {}\n\ntype R struct {\n\t// Key [a]\n\t//\n\t// [a]: https://a\n\tKey *string\n}\n"
+	want := "// Search see [docs]\n// Since 6.3\n//\n// [docs]: https://docs.sonarqube.org\nfunc Search() {}\n\ntype R struct {\n\t// Key [a]\n\t//\n\t// [a]: https://a\n\tKey *string\n}\n"
+	if got := string(moveDocLinks([]byte(src))); got != want {
+		t.Errorf("moveDocLinks() = %q, want %q", got, want)
+	}
+}
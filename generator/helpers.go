@@ -0,0 +1,190 @@
+package generator
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+func tick() string {
+	return "`"
+}
+
+func formatSince(since Version) string {
+	return since.String()
+}
+
+var tagRE = regexp.MustCompile("<[^>]*>")
+
+// plainText strips html tags and entities, and joins the text into a single line
+func plainText(str string) string {
+	str = html.UnescapeString(tagRE.ReplaceAllString(str, " "))
+	return strings.Join(strings.Fields(str), " ")
+}
+
+func quote(str string) string {
+	return strconv.Quote(str)
+}
+
+// flagUsage returns the usage of a command line flag for the param
+func flagUsage(p *Param) string {
+	usage := make([]string, 0, 4)
+	if p.Required {
+		usage = append(usage, "(required)")
+	}
+	if description := plainText(p.Description); description != "" {
+		usage = append(usage, description)
+	}
+	if len(p.PossibleValues) != 0 {
+		usage = append(usage, "Possible values: "+strings.Join(p.PossibleValues, ", ")+".")
+	}
+	if p.IsFile() {
+		usage = append(usage, "Path of the file to upload.")
+	}
+	if p.IsList() {
+		usage = append(usage, "Comma-separated list.")
+	}
+	if p.DefaultValue != "" {
+		usage = append(usage, "Default: "+p.DefaultValue+".")
+	}
+	return strings.Join(usage, " ")
+}
+
+var (
+	markdownLinkRE = regexp.MustCompile(`(?is)<a\s[^>]*href\s*=\s*["']([^"']*)["'][^>]*>(.*?)</a>`)
+	markdownTagRE  = regexp.MustCompile(`(?i)</?(p|ul|ol|li|br|code|pre|strong|b|em|i|h[1-6])(\s[^>]*)?/?>`)
+)
+
+// markdown converts html of SonarQube descriptions to markdown
+func markdown(str string) string {
+	str = markdownLinkRE.ReplaceAllString(str, "[$2]($1)")
+	str = markdownTagRE.ReplaceAllStringFunc(str, func(tag string) string {
+		closing := strings.HasPrefix(tag, "</")
+		name := strings.ToLower(markdownTagRE.FindStringSubmatch(tag)[1])
+		switch name {
+		case "br":
+			return "  \n"
+		case "p", "ul", "ol":
+			return "\n\n"
+		case "li":
+			if closing {
+				return ""
+			}
+			return "\n- "
+		case "code":
+			return "`"
+		case "pre":
+			return "\n```\n"
+		case "strong", "b":
+			return "**"
+		case "em", "i":
+			return "_"
+		default:
+			if closing {
+				return "\n\n"
+			}
+			return "\n\n#### "
+		}
+	})
+	lines := strings.Split(strings.TrimSpace(str), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimLeft(line, " \t")
+	}
+	return multipleNewLinesRE.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+}
+
+var multipleNewLinesRE = regexp.MustCompile("\n{3,}")
+
+// markdownCell converts html to a single line, which can be used in a markdown table cell
+func markdownCell(str string) string {
+	return strings.ReplaceAll(plainText(str), "|", "\\|")
+}
+
+// joinWords joins the words of the string, the first word is transformed by first, the others by next
+func joinWords(str, sep string, first, next func(string) string) string {
+	words := splitWords(str)
+	for i, word := range words {
+		if i == 0 {
+			words[i] = first(word)
+		} else {
+			words[i] = next(word)
+		}
+	}
+	return strings.Join(words, sep)
+}
+
+func capitalize(word string) string {
+	return makeExported(strings.ToLower(word))
+}
+
+// camel converts the string to camelCase, e.g. project_key to projectKey
+func camel(str string) string {
+	return joinWords(str, "", strings.ToLower, capitalize)
+}
+
+// pascal converts the string to PascalCase, e.g. project_key to ProjectKey
+func pascal(str string) string {
+	return joinWords(str, "", capitalize, capitalize)
+}
+
+// snake converts the string to snake_case, e.g. projectKey to project_key
+func snake(str string) string {
+	return joinWords(str, "_", strings.ToLower, strings.ToLower)
+}
+
+// kebab converts the string to kebab-case, e.g. projectKey to project-key
+func kebab(str string) string {
+	return joinWords(str, "-", strings.ToLower, strings.ToLower)
+}
+
+// dict builds a map from key and value pairs, e.g. for type mapping: {{index (dict "list" "string[]" "bool" "boolean") .Kind}}
+func dict(pairs ...interface{}) (map[string]interface{}, error) {
+	if len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("dict expects key and value pairs, got %d arguments", len(pairs))
+	}
+	result := make(map[string]interface{}, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict key %v is not a string", pairs[i])
+		}
+		result[key] = pairs[i+1]
+	}
+	return result, nil
+}
+
+func list(items ...interface{}) []interface{} {
+	return items
+}
+
+// newTemplateHelpers returns the functions of templates, descriptions are wrapped to docWidth
+func newTemplateHelpers(docWidth int) template.FuncMap {
+	return template.FuncMap{
+		"formatDescription": func(str string) string { return formatDoc(str, docWidth) },
+		"tick":              tick,
+		"formatSince":       formatSince,
+		"plainText":         plainText,
+		"quote":             quote,
+		"flagUsage":         flagUsage,
+		"markdown":          markdown,
+		"markdownCell":      markdownCell,
+		"join":              strings.Join,
+		// case transforms
+		"upper":     strings.ToUpper,
+		"lower":     strings.ToLower,
+		"camel":     camel,
+		"pascal":    pascal,
+		"snake":     snake,
+		"kebab":     kebab,
+		"goName":    func(str string) string { return goName(str, true) },
+		"goVarName": func(str string) string { return goName(str, false) },
+		// collections, e.g. for type mapping
+		"dict": dict,
+		"list": list,
+		// functions of the template dir partials, see parseTemplate
+		"include": include(nil),
+	}
+}
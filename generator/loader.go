@@ -0,0 +1,760 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultVersionNumber = 0
+	defaultPackageName   = "sonarqube_client"
+	serviceSuffix        = "Service"
+	requestSuffix        = "Request"
+	responseSuffix       = "Response"
+	urlPrefix            = "api/"
+	fileExt              = ".go"
+	webservicesUrl       = "/api/webservices/list"
+	includeInternalUrl   = "?include_internals=true"
+	serverVersionUrl     = "/api/server/version"
+	responseExampleUrl   = "/api/webservices/response_example"
+	defaultVersionString = "0.0"
+	maxVersionSize       = 1 << 10
+	maxDefinitionSize    = 64 << 20
+	maxSnippetLength     = 200
+)
+
+// DefaultTimeout is the timeout of requests to the server of the default http client
+const DefaultTimeout = 30 * time.Second
+
+// Version is the major and minor version of the server or the one the api was added in
+type Version struct {
+	major byte
+	minor byte
+	str   string
+}
+
+func newVersion(s string) *Version {
+	v := &Version{}
+	if strings.TrimSpace(s) == "" {
+		s = defaultVersionString
+	}
+	v.UnmarshalJSON([]byte(s))
+	return v
+}
+
+func (v *Version) String() string {
+	return v.str
+}
+
+func (v *Version) Major() int {
+	return int(v.major)
+}
+
+func (v *Version) Minor() int {
+	return int(v.minor)
+}
+
+func (v *Version) UnmarshalJSON(raw []byte) error {
+	v.str = strings.Trim(string(raw), "\"")
+	seg := strings.Split(v.str, ".")
+	major, err := strconv.ParseInt(seg[0], 10, 8)
+	if err != nil {
+		return fmt.Errorf("failed to pars major version, str - %v：%w", v.str, err)
+	}
+	v.major = byte(major)
+
+	if len(seg) >= 2 {
+		minor, err := strconv.ParseInt(seg[1], 10, 8)
+		if err != nil {
+			return fmt.Errorf("failed to pars minor version, str - %v：%w", v.str, err)
+		}
+		v.minor = byte(minor)
+	} else {
+		v.minor = defaultVersionNumber
+	}
+	return nil
+}
+
+func (v *Version) lessOrEqual(ov *Version) bool {
+	switch {
+	case v.major > ov.major:
+		// 3.3, 2.2 => false
+		return false
+	case v.major < ov.major:
+		// 1.1, 2.2 => true
+		return true
+	case v.minor > ov.minor:
+		// 1.3, 1.2 => false
+		return false
+	case v.minor < ov.minor:
+		// 1.1, 1.2 => true
+		return true
+	default:
+		// 1.1, 1.1 => false
+		return true
+	}
+}
+
+func (v *Version) greater(ov *Version) bool {
+	return !v.lessOrEqual(ov)
+}
+
+func (v *Version) isSet() bool {
+	return v.major != defaultVersionNumber && v.minor != defaultVersionNumber
+}
+
+// APIDefinition is the api of a server version, the model of templates
+type APIDefinition struct {
+	Host        string
+	PackageName string
+	Version     *Version
+	WebServices []*WebService
+	// CommonImport is the import path of the common package of versioned packages,
+	// it's empty if the package is self-contained
+	CommonImport string
+
+	warnings []string
+	ext      *extensions
+	// hash is the sha256 of the raw definition
+	hash string
+}
+
+// ClientExtType returns the extension type embedded into the client, or empty string if it's not declared
+func (ad *APIDefinition) ClientExtType() string {
+	if name := "Client" + extTypeSuffix; ad.ext.hasType(name) {
+		return name
+	}
+	return ""
+}
+
+// HasClientExtInit is true if the init method of the client is declared
+func (ad *APIDefinition) HasClientExtInit() bool {
+	return ad.ext.hasMethod("Client", extInitMethod)
+}
+
+func (ad *APIDefinition) ensurePackageName() {
+	if ad.PackageName == "" {
+		ad.PackageName = defaultPackageName
+	}
+}
+
+func (ad *APIDefinition) setPackageName(name string) {
+	ad.PackageName = name
+	for _, service := range ad.WebServices {
+		service.PackageName = name
+	}
+}
+
+// WebService is a service of the api, e.g. api/projects
+type WebService struct {
+	PackageName string
+	Path        string
+	Since       Version
+	Description string
+	Actions     []*Action
+
+	// getter is the Go name assigned by nameDefinition
+	getter string
+	ext    *extensions
+}
+
+// ExtType returns the extension type embedded into the service, or empty string if it's not declared
+func (ws *WebService) ExtType() string {
+	if name := ws.ServiceName() + extTypeSuffix; ws.ext.hasType(name) {
+		return name
+	}
+	return ""
+}
+
+// HasExtInit is true if the init method of the service is declared
+func (ws *WebService) HasExtInit() bool {
+	return ws.ext.hasMethod(ws.ServiceName(), extInitMethod)
+}
+
+func (ws *WebService) Internal() bool {
+	for _, action := range ws.Actions {
+		if !action.Internal {
+			return false
+		}
+	}
+	return true
+}
+
+func (ws *WebService) Deprecated() bool {
+	for _, action := range ws.Actions {
+		if !action.DeprecatedSince.isSet() {
+			return false
+		}
+	}
+	return true
+}
+
+func (ws *WebService) ServiceName() string {
+	return ws.Getter() + serviceSuffix
+}
+
+func (ws *WebService) Variable() string {
+	return makeUnexported(ws.ServiceName())
+}
+
+func (ws *WebService) Getter() string {
+	if ws.getter != "" {
+		return ws.getter
+	}
+	return goName(ws.Name(), true)
+}
+
+// Name returns the service path without the api prefix, e.g. projects
+func (ws *WebService) Name() string {
+	return strings.TrimPrefix(ws.Path, urlPrefix)
+}
+
+// HasRequests reports whether any of the service actions takes parameters
+func (ws *WebService) HasRequests() bool {
+	for _, action := range ws.Actions {
+		if len(action.Params) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// HasResponses reports whether any of the service actions returns *http.Response
+func (ws *WebService) HasResponses() bool {
+	for _, action := range ws.Actions {
+		if !action.IsStream() {
+			return true
+		}
+	}
+	return false
+}
+
+func (ws *WebService) fileName() string {
+	return ws.Name() + fileExt
+}
+
+// Action is an action of a service, e.g. api/projects/search
+type Action struct {
+	ServiceName        string
+	Key                string
+	Description        string
+	Since              Version
+	Internal           bool
+	Post               bool
+	HasResponseExample bool
+	DeprecatedSince    Version
+	Changelog          []*change
+	Params             []*Param
+	// ResponseExample isn't a part of the list, it's loaded separately on demand
+	ResponseExample *responseExample `json:"-"`
+
+	// methodName is the Go name assigned by nameDefinition
+	methodName string
+	// stream is set by markStreamActions for actions returning binary or text content
+	stream bool
+	// service is the service of the action, it's set after decoding
+	service *WebService
+}
+
+// Service returns the service of the action
+func (a *Action) Service() *WebService {
+	return a.service
+}
+
+// Path returns the path of the action, e.g. api/projects/search
+func (a *Action) Path() string {
+	if a.service == nil {
+		return a.Key
+	}
+	return a.service.Path + "/" + a.Key
+}
+
+// HTTPMethod returns GET or POST
+func (a *Action) HTTPMethod() string {
+	if a.Post {
+		return http.MethodPost
+	}
+	return http.MethodGet
+}
+
+// RequiredParams returns the params which must be set
+func (a *Action) RequiredParams() []*Param {
+	result := make([]*Param, 0, len(a.Params))
+	for _, p := range a.Params {
+		if p.Required {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// OptionalParams returns the params which can be omitted
+func (a *Action) OptionalParams() []*Param {
+	result := make([]*Param, 0, len(a.Params))
+	for _, p := range a.Params {
+		if !p.Required {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+type responseExample struct {
+	Format  string
+	Example string
+}
+
+func (a *Action) MethodName() string {
+	if a.methodName != "" {
+		return a.methodName
+	}
+	return goName(a.Key, true)
+}
+
+func (a *Action) RequestTypeName() string {
+	return a.ServiceName + a.MethodName() + requestSuffix
+}
+
+func (a *Action) ResponseTypeName() string {
+	return a.ServiceName + a.MethodName() + responseSuffix
+}
+
+func (a *Action) Deprecated() bool {
+	return a.DeprecatedSince.isSet()
+}
+
+// HasDeprecatedKeys reports whether any of the action params has a deprecated key
+func (a *Action) HasDeprecatedKeys() bool {
+	for _, p := range a.Params {
+		if p.DeprecatedKey != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsStream is true for actions which response is returned as a download instead of *http.Response
+func (a *Action) IsStream() bool {
+	return a.stream
+}
+
+// HasFiles is true for actions uploading files, they are sent as multipart/form-data
+func (a *Action) HasFiles() bool {
+	for _, p := range a.Params {
+		if p.IsFile() {
+			return true
+		}
+	}
+	return false
+}
+
+type change struct {
+	Description string
+	Version     string
+}
+
+func (c *change) String() string {
+	return c.Version + ": " + strings.ReplaceAll(c.Description, "\n", "")
+}
+
+// Param is a param of an action, e.g. api/projects/search?ps
+type Param struct {
+	Key                string
+	Since              Version
+	Description        string
+	Required           bool
+	Internal           bool
+	ExampleValue       string
+	DeprecatedSince    Version
+	PossibleValues     []string
+	DeprecatedKey      string
+	DeprecatedKeySince Version
+	DefaultValue       string
+	MaximumValue       int
+	MinimumLength      int
+	MaximumLength      int
+	MaxValuesAllowed   int
+
+	// name is the Go name assigned by nameDefinition
+	name string
+	// file is set by markFileParams for params sent as files
+	file bool
+}
+
+func (p *Param) ParamName() string {
+	if p.name != "" {
+		return p.name
+	}
+	return goName(p.Key, true)
+}
+
+func (p *Param) Deprecated() bool {
+	return p.DeprecatedSince.isSet()
+}
+
+// IsList reports whether the param accepts a comma-separated list of values
+func (p *Param) IsList() bool {
+	return p.MaxValuesAllowed > 0
+}
+
+// IsBool reports whether the param is a boolean flag, i.e. its possible values are true/false or yes/no
+func (p *Param) IsBool() bool {
+	if p.IsList() || len(p.PossibleValues) == 0 {
+		return false
+	}
+	for _, v := range p.PossibleValues {
+		switch v {
+		case "true", "false", "yes", "no":
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// TrueValue returns the wire representation of true for a boolean param
+func (p *Param) TrueValue() string {
+	if p.yesNo() {
+		return "yes"
+	}
+	return "true"
+}
+
+// FalseValue returns the wire representation of false for a boolean param
+func (p *Param) FalseValue() string {
+	if p.yesNo() {
+		return "no"
+	}
+	return "false"
+}
+
+func (p *Param) yesNo() bool {
+	for _, v := range p.PossibleValues {
+		if v == "true" || v == "false" {
+			return false
+		}
+	}
+	return true
+}
+
+// Kind classifies the param by its values: file, list, bool, integer, enum or string
+func (p *Param) Kind() string {
+	switch {
+	case p.IsFile():
+		return "file"
+	case p.IsList():
+		return "list"
+	case p.IsBool():
+		return "bool"
+	case p.IsInteger():
+		return "integer"
+	case p.IsEnum():
+		return "enum"
+	default:
+		return "string"
+	}
+}
+
+// IsInteger is true for params with the maximum value
+func (p *Param) IsInteger() bool {
+	return p.MaximumValue != 0
+}
+
+// IsEnum is true for params with possible values, except bool ones
+func (p *Param) IsEnum() bool {
+	return len(p.PossibleValues) != 0 && !p.IsBool()
+}
+
+// GoType returns the type of the request field for the param
+func (p *Param) GoType() string {
+	switch {
+	case p.IsFile():
+		return "*File"
+	case p.IsList():
+		return "[]string"
+	case p.IsBool():
+		return "*bool"
+	default:
+		return "*string"
+	}
+}
+
+// IsFile is true for params which content is uploaded as a file
+func (p *Param) IsFile() bool {
+	return p.file
+}
+
+type filter struct {
+	internal   bool
+	deprecated bool
+	version    *Version
+}
+
+func url(host string, internal bool) string {
+	link := host + webservicesUrl
+	if internal {
+		link += includeInternalUrl
+	}
+	return link
+}
+
+// httpStatusError is returned by the loader when the server responds with an unexpected status
+type httpStatusError struct {
+	url     string
+	status  int
+	snippet string
+}
+
+func (e *httpStatusError) Error() string {
+	msg := fmt.Sprintf("%s responded with %d %s", e.url, e.status, http.StatusText(e.status))
+	switch {
+	case e.status == http.StatusUnauthorized:
+		msg += " (authentication failed, check credentials)"
+	case e.status == http.StatusForbidden:
+		msg += " (insufficient permissions)"
+	case e.status == http.StatusNotFound:
+		msg += " (check that the host points to a SonarQube server)"
+	case e.status >= http.StatusInternalServerError:
+		msg += " (server error)"
+	}
+	if e.snippet != "" {
+		msg += "：" + e.snippet
+	}
+	return msg
+}
+
+func snippet(body []byte) string {
+	s := strings.Join(strings.Fields(string(body)), " ")
+	if len(s) > maxSnippetLength {
+		s = s[:maxSnippetLength] + "..."
+	}
+	return s
+}
+
+// fetch sends the request and returns the response body, at most limit bytes are read
+func fetch(client *http.Client, req *http.Request, limit int64) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed：%w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from %s：%w", req.URL.Redacted(), err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &httpStatusError{
+			url:     req.URL.Redacted(),
+			status:  resp.StatusCode,
+			snippet: snippet(body),
+		}
+	}
+
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("response from %s exceeds %d bytes", req.URL.Redacted(), limit)
+	}
+	return body, nil
+}
+
+func newRequest(url string, auth string) (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	return req, nil
+}
+
+func getTargetVersion(client *http.Client, host, auth, version string) (string, error) {
+	if version != "" {
+		return version, nil
+	}
+	req, err := newRequest(host+serverVersionUrl, auth)
+	if err != nil {
+		return "", fmt.Errorf("failed to create server version request：%w", err)
+	}
+	body, err := fetch(client, req, maxVersionSize)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch server version：%w", err)
+	}
+	version = strings.TrimSpace(string(body))
+	if version == "" {
+		return "", errors.New("failed to fetch server version：empty response")
+	}
+
+	return version, nil
+}
+
+func getDefinition(client *http.Client, host string, auth string, internal bool, strict bool, version *Version) (*APIDefinition, error) {
+	req, err := newRequest(url(host, internal), auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api definitions request：%w", err)
+	}
+	body, err := fetch(client, req, maxDefinitionSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch api definitions：%w", err)
+	}
+	return decodeAPI(body, host, strict, version)
+}
+
+// readDefinition reads the definition from a snapshot, a file with the response of api/webservices/list
+func readDefinition(path string, host string, strict bool, version *Version) (*APIDefinition, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read api definitions snapshot：%w", err)
+	}
+	return decodeAPI(body, host, strict, version)
+}
+
+func decodeAPI(body []byte, host string, strict bool, version *Version) (*APIDefinition, error) {
+
+	def := &APIDefinition{
+		Host:    host,
+		Version: version,
+		hash:    definitionHash(body),
+	}
+	warnings, err := decodeDefinition(body, def, strict)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response (%s)：%w", snippet(body), err)
+	}
+	def.warnings = warnings
+
+	def.ensurePackageName()
+	for _, service := range def.WebServices {
+		service.PackageName = def.PackageName
+		for _, action := range service.Actions {
+			action.ServiceName = service.ServiceName()
+			action.service = service
+		}
+	}
+
+	return def, nil
+}
+
+// getResponseExamples loads response examples of the actions which have them
+func getResponseExamples(client *http.Client, host string, auth string, def *APIDefinition) error {
+	for _, service := range def.WebServices {
+		for _, action := range service.Actions {
+			if !action.HasResponseExample {
+				continue
+			}
+			query := neturl.Values{"controller": {service.Path}, "action": {action.Key}}
+			req, err := newRequest(host+responseExampleUrl+"?"+query.Encode(), auth)
+			if err != nil {
+				return fmt.Errorf("failed to create response example request：%w", err)
+			}
+			body, err := fetch(client, req, maxDefinitionSize)
+			if err != nil {
+				return fmt.Errorf("failed to fetch response example of %s/%s：%w", service.Path, action.Key, err)
+			}
+			example := &responseExample{}
+			if err := json.Unmarshal(body, example); err != nil {
+				return fmt.Errorf("failed to decode response example of %s/%s：%w", service.Path, action.Key, err)
+			}
+			action.ResponseExample = example
+		}
+	}
+	return nil
+}
+
+func filterParams(params []*Param, f *filter) []*Param {
+	result := make([]*Param, 0, len(params))
+	for _, p := range params {
+		if !f.deprecated && p.Deprecated() ||
+			!f.internal && p.Internal ||
+			p.Since.greater(f.version) {
+			continue
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+func filterActions(actions []*Action, f *filter) []*Action {
+	result := make([]*Action, 0, len(actions))
+	for _, action := range actions {
+
+		if !f.deprecated && action.Deprecated() ||
+			!f.internal && action.Internal ||
+			action.Since.greater(f.version) {
+			continue
+		}
+
+		action.Params = filterParams(action.Params, f)
+		result = append(result, action)
+	}
+	return result
+}
+
+func filterDefinition(def *APIDefinition, f *filter) *APIDefinition {
+	wss := make([]*WebService, 0, len(def.WebServices))
+	for _, ws := range def.WebServices {
+
+		if !f.deprecated && ws.Deprecated() ||
+			!f.internal && ws.Internal() ||
+			ws.Since.greater(f.version) {
+			continue
+		}
+
+		ws.Actions = filterActions(ws.Actions, f)
+
+		wss = append(wss, ws)
+	}
+	def.WebServices = wss
+	return def
+}
+
+// loadAPI loads the definition of the target version from the server,
+// or from the snapshot file if it's set (the version is required then)
+func loadAPI(client *http.Client, host string, deprecated bool, internal bool, strict bool, examples bool, version string, snapshot string, auth string) (*APIDefinition, error) {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	if snapshot != "" && version == "" {
+		return nil, fmt.Errorf("target version of snapshot %s is required", snapshot)
+	}
+
+	version, err := getTargetVersion(client, host, auth, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve target version：%w", err)
+	}
+	parsedVersion := newVersion(version)
+
+	var def *APIDefinition
+	if snapshot != "" {
+		def, err = readDefinition(snapshot, host, strict, parsedVersion)
+	} else {
+		def, err = getDefinition(client, host, auth, internal, strict, parsedVersion)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load definition：%w", err)
+	}
+
+	filterDefinition(def, &filter{
+		deprecated: deprecated,
+		internal:   internal,
+		version:    parsedVersion,
+	})
+
+	if examples && snapshot != "" {
+		def.warnings = append(def.warnings, fmt.Sprintf("response examples aren't loaded for snapshot %s", snapshot))
+	} else if examples {
+		if err := getResponseExamples(client, host, auth, def); err != nil {
+			return nil, fmt.Errorf("failed to load response examples：%w", err)
+		}
+	}
+
+	return def, nil
+}
@@ -1,10 +1,11 @@
-package main
+package generator
 
 import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -17,14 +18,14 @@ var (
 	fixtureHost          = "http://localhost:9000"
 )
 
-type apiDefinitionWith func(*apiDefinition)
+type apiDefinitionWith func(*APIDefinition)
 
-func createAPIDefinition(options ...apiDefinitionWith) *apiDefinition {
-	a := &apiDefinition{
+func createAPIDefinition(options ...apiDefinitionWith) *APIDefinition {
+	a := &APIDefinition{
 		PackageName: fixturePackageName,
 		Version:     fixtureVersion00,
 		Host:        fixtureHost,
-		WebServices: make([]*webService, 0, 0),
+		WebServices: make([]*WebService, 0, 0),
 	}
 
 	for _, option := range options {
@@ -34,20 +35,20 @@ func createAPIDefinition(options ...apiDefinitionWith) *apiDefinition {
 	return a
 }
 
-func apiDefinitionWithWebServices(ws ...*webService) apiDefinitionWith {
-	return func(d *apiDefinition) {
+func apiDefinitionWithWebServices(ws ...*WebService) apiDefinitionWith {
+	return func(d *APIDefinition) {
 		d.WebServices = append(d.WebServices, ws...)
 	}
 }
 
-type webServiceWith func(*webService)
+type webServiceWith func(*WebService)
 
-func createWebService(options ...webServiceWith) *webService {
-	ws := &webService{
+func createWebService(options ...webServiceWith) *WebService {
+	ws := &WebService{
 		PackageName: fixturePackageName,
 		Path:        "/api/normal",
 		Since:       *fixtureVersion00,
-		Actions:     []*action{},
+		Actions:     []*Action{},
 	}
 
 	for _, option := range options {
@@ -57,25 +58,25 @@ func createWebService(options ...webServiceWith) *webService {
 	return ws
 }
 
-func webServiceWithSince(v version) webServiceWith {
-	return func(ws *webService) {
+func webServiceWithSince(v Version) webServiceWith {
+	return func(ws *WebService) {
 		ws.Since = v
 	}
 }
 
-func webServiceWithActions(a ...*action) webServiceWith {
-	return func(ws *webService) {
+func webServiceWithActions(a ...*Action) webServiceWith {
+	return func(ws *WebService) {
 		ws.Actions = append(ws.Actions, a...)
 	}
 }
 
-type actionWith func(*action)
+type actionWith func(*Action)
 
-func createAction(options ...actionWith) *action {
-	a := &action{
+func createAction(options ...actionWith) *Action {
+	a := &Action{
 		Key:    "normal",
 		Since:  *fixtureVersion00,
-		Params: []*param{},
+		Params: []*Param{},
 	}
 
 	for _, option := range options {
@@ -86,27 +87,27 @@ func createAction(options ...actionWith) *action {
 }
 
 func actionInternal() actionWith {
-	return func(a *action) {
+	return func(a *Action) {
 		a.Internal = true
 	}
 }
 
-func actionSince(v version) actionWith {
-	return func(a *action) {
+func actionSince(v Version) actionWith {
+	return func(a *Action) {
 		a.Since = v
 	}
 }
 
-func actionDeprecatedSince(v version) actionWith {
-	return func(a *action) {
+func actionDeprecatedSince(v Version) actionWith {
+	return func(a *Action) {
 		a.DeprecatedSince = v
 	}
 }
 
-type paramWith func(*param)
+type paramWith func(*Param)
 
-func createParam(options ...paramWith) *param {
-	p := &param{
+func createParam(options ...paramWith) *Param {
+	p := &Param{
 		Key:                "param_key",
 		Since:              *fixtureVersion00,
 		Description:        "Description",
@@ -131,21 +132,21 @@ func createParam(options ...paramWith) *param {
 	return p
 }
 
-func paramDeprecatedSince(v version) paramWith {
-	return func(p *param) {
+func paramDeprecatedSince(v Version) paramWith {
+	return func(p *Param) {
 		p.DeprecatedSince = v
 	}
 }
 
 func Test_filterDefinition(t *testing.T) {
 	type args struct {
-		def *apiDefinition
+		def *APIDefinition
 		f   *filter
 	}
 	tests := []struct {
 		name string
 		args args
-		want *apiDefinition
+		want *APIDefinition
 	}{
 		{
 			name: "should remove an deprecated service if 'deprecated' param is false",
@@ -287,18 +288,18 @@ func Test_filterDefinition(t *testing.T) {
 
 func Test_filterActions(t *testing.T) {
 	type args struct {
-		actions []*action
+		actions []*Action
 		f       *filter
 	}
 	tests := []struct {
 		name string
 		args args
-		want []*action
+		want []*Action
 	}{
 		{
 			name: "should remove deprecated actions if deprecated = false",
 			args: args{
-				actions: []*action{
+				actions: []*Action{
 					createAction(actionDeprecatedSince(*fixtureVersion11)),
 					createAction(),
 				},
@@ -308,12 +309,12 @@ func Test_filterActions(t *testing.T) {
 					version:    fixtureVersion00,
 				},
 			},
-			want: []*action{createAction()},
+			want: []*Action{createAction()},
 		},
 		{
 			name: "should keep deprecated actions if deprecated = true",
 			args: args{
-				actions: []*action{
+				actions: []*Action{
 					createAction(actionDeprecatedSince(*fixtureVersion11)),
 					createAction(),
 				},
@@ -323,7 +324,7 @@ func Test_filterActions(t *testing.T) {
 					version:    fixtureVersion00,
 				},
 			},
-			want: []*action{
+			want: []*Action{
 				createAction(actionDeprecatedSince(*fixtureVersion11)),
 				createAction(),
 			},
@@ -331,7 +332,7 @@ func Test_filterActions(t *testing.T) {
 		{
 			name: "should remove internal actions if internal = false",
 			args: args{
-				actions: []*action{
+				actions: []*Action{
 					createAction(actionInternal()),
 					createAction(),
 				},
@@ -341,12 +342,12 @@ func Test_filterActions(t *testing.T) {
 					version:    fixtureVersion00,
 				},
 			},
-			want: []*action{createAction()},
+			want: []*Action{createAction()},
 		},
 		{
 			name: "should keep internal actions if internal = true",
 			args: args{
-				actions: []*action{
+				actions: []*Action{
 					createAction(actionInternal()),
 					createAction(),
 				},
@@ -356,7 +357,7 @@ func Test_filterActions(t *testing.T) {
 					version:    fixtureVersion00,
 				},
 			},
-			want: []*action{
+			want: []*Action{
 				createAction(actionInternal()),
 				createAction(),
 			},
@@ -364,7 +365,7 @@ func Test_filterActions(t *testing.T) {
 		{
 			name: "should remove action if Since greater than target version",
 			args: args{
-				actions: []*action{
+				actions: []*Action{
 					createAction(
 						actionSince(*fixtureVersion44),
 					),
@@ -376,7 +377,7 @@ func Test_filterActions(t *testing.T) {
 					version:    fixtureVersion11,
 				},
 			},
-			want: []*action{
+			want: []*Action{
 				createAction(),
 			},
 		},
@@ -392,25 +393,25 @@ func Test_filterActions(t *testing.T) {
 
 func Test_filterParams(t *testing.T) {
 	type args struct {
-		params []*param
+		params []*Param
 		f      *filter
 	}
 	tests := []struct {
 		name string
 		args args
-		want []*param
+		want []*Param
 	}{
 		{
 			name: "should remove deprecated param",
 			args: args{
-				params: []*param{createParam(), createParam(paramDeprecatedSince(*fixtureVersion11))},
+				params: []*Param{createParam(), createParam(paramDeprecatedSince(*fixtureVersion11))},
 				f: &filter{
 					deprecated: false,
 					internal:   false,
 					version:    fixtureVersion11,
 				},
 			},
-			want: []*param{createParam()},
+			want: []*Param{createParam()},
 		},
 	}
 	for _, tt := range tests {
@@ -430,6 +431,12 @@ func Test_getTargetVersion(t *testing.T) {
 			fmt.Fprintf(w, "2.3")
 		case "/bad_request/api/server/version":
 			http.Error(w, "Bad Request", http.StatusBadRequest)
+		case "/auth/api/server/version":
+			if r.Header.Get("Authorization") != "Basic dG9rZW46" {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprintf(w, "2.4")
 		}
 	}))
 
@@ -438,6 +445,7 @@ func Test_getTargetVersion(t *testing.T) {
 	type args struct {
 		client  *http.Client
 		host    string
+		auth    string
 		version string
 	}
 	tests := []struct {
@@ -476,10 +484,21 @@ func Test_getTargetVersion(t *testing.T) {
 			want:    "",
 			wantErr: true,
 		},
+		{
+			name: "should send authorization header",
+			args: args{
+				client:  http.DefaultClient,
+				host:    ts.URL + "/auth",
+				auth:    "Basic dG9rZW46",
+				version: "",
+			},
+			want:    "2.4",
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := getTargetVersion(tt.args.client, tt.args.host, tt.args.version)
+			got, err := getTargetVersion(tt.args.client, tt.args.host, tt.args.auth, tt.args.version)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("getTargetVersion() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -490,3 +509,163 @@ func Test_getTargetVersion(t *testing.T) {
 		})
 	}
 }
+
+func Test_param_GoType(t *testing.T) {
+	tests := []struct {
+		name       string
+		param      *Param
+		want       string
+		wantTrue   string
+		wantFalse  string
+		wantIsBool bool
+	}{
+		{
+			name:  "should be a string by default",
+			param: createParam(),
+			want:  "*string",
+		},
+		{
+			name:       "should be a bool if possible values are true/false",
+			param:      createParam(func(p *Param) { p.PossibleValues = []string{"true", "false"} }),
+			want:       "*bool",
+			wantTrue:   "true",
+			wantFalse:  "false",
+			wantIsBool: true,
+		},
+		{
+			name:       "should be a bool encoded as yes/no if possible values are yes/no",
+			param:      createParam(func(p *Param) { p.PossibleValues = []string{"yes", "no"} }),
+			want:       "*bool",
+			wantTrue:   "yes",
+			wantFalse:  "no",
+			wantIsBool: true,
+		},
+		{
+			name:  "should be a string if possible values are an enum",
+			param: createParam(func(p *Param) { p.PossibleValues = []string{"TRK", "VW"} }),
+			want:  "*string",
+		},
+		{
+			name:  "should be a list if multiple values are allowed",
+			param: createParam(func(p *Param) { p.MaxValuesAllowed = 10 }),
+			want:  "[]string",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.param.GoType(); got != tt.want {
+				t.Errorf("GoType() = %v, want %v", got, tt.want)
+			}
+			if !tt.wantIsBool {
+				return
+			}
+			if got := tt.param.TrueValue(); got != tt.wantTrue {
+				t.Errorf("TrueValue() = %v, want %v", got, tt.wantTrue)
+			}
+			if got := tt.param.FalseValue(); got != tt.wantFalse {
+				t.Errorf("FalseValue() = %v, want %v", got, tt.wantFalse)
+			}
+		})
+	}
+}
+
+func Test_fetch(t *testing.T) {
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			fmt.Fprint(w, "0123456789")
+		case "/unauthorized":
+			http.Error(w, `{"errors":[{"msg":"Authentication is required"}]}`, http.StatusUnauthorized)
+		case "/error":
+			http.Error(w, "<html>\n  <body>Internal   error</body>\n</html>", http.StatusInternalServerError)
+		}
+	}))
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	tests := []struct {
+		name      string
+		url       string
+		limit     int64
+		want      string
+		wantInErr string
+	}{
+		{
+			name:  "should return body of successful response",
+			url:   ts.URL + "/ok",
+			limit: 10,
+			want:  "0123456789",
+		},
+		{
+			name:      "should fail if body exceeds limit",
+			url:       ts.URL + "/ok",
+			limit:     5,
+			wantInErr: "exceeds 5 bytes",
+		},
+		{
+			name:      "should classify authentication errors",
+			url:       ts.URL + "/unauthorized",
+			limit:     100,
+			wantInErr: "401 Unauthorized (authentication failed, check credentials)：{\"errors\":[{\"msg\":\"Authentication is required\"}]}",
+		},
+		{
+			name:      "should report unexpected statuses with a body snippet",
+			url:       ts.URL + "/error",
+			limit:     100,
+			wantInErr: "500 Internal Server Error (server error)：<html> <body>Internal error</body> </html>",
+		},
+		{
+			name:      "should return connection errors",
+			url:       closed.URL + "/ok",
+			limit:     100,
+			wantInErr: "request failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+			got, err := fetch(http.DefaultClient, req, tt.limit)
+			if tt.wantInErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantInErr) {
+					t.Errorf("fetch() error = %v, want error containing %q", err, tt.wantInErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("fetch() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("fetch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_decodeDefinition(t *testing.T) {
+	body := []byte(`{"webServices":[{"path":"api/projects","since":"2.10","newAttribute":1,"actions":[
+		{"key":"create","since":"4.0","responseExample":{},"params":[{"key":"name","minimumValue":1},{"key":"project","minimumValue":2}]}
+	]}]}`)
+
+	def := &APIDefinition{}
+	warnings, err := decodeDefinition(body, def, false)
+	if err != nil {
+		t.Fatalf("decodeDefinition() error = %v", err)
+	}
+	want := []string{
+		"unknown field webServices[].actions[].params[].minimumValue is ignored (2 occurrences)",
+		"unknown field webServices[].actions[].responseExample is ignored (1 occurrences)",
+		"unknown field webServices[].newAttribute is ignored (1 occurrences)",
+	}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("decodeDefinition() warnings = %v, want %v", warnings, want)
+	}
+	if got := def.WebServices[0].Actions[0].Params[1].Key; got != "project" {
+		t.Errorf("decodeDefinition() decoded param key = %v, want project", got)
+	}
+
+	if _, err := decodeDefinition(body, &APIDefinition{}, true); err == nil {
+		t.Errorf("decodeDefinition() in strict mode should fail on unknown fields")
+	}
+}
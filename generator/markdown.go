@@ -0,0 +1,37 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+)
+
+const (
+	markdownTemplateName  = "markdown.tpl"
+	markdownIndexFileName = "README.md"
+	markdownFileExt       = ".md"
+)
+
+// renderMarkdown renders the api reference, a file per service and the index file
+func (g *Generator) renderMarkdown(def *APIDefinition) (map[string][]byte, error) {
+	markdownTemplate, err := g.parseTemplate(markdownTemplateName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte, len(def.WebServices)+1)
+	for _, service := range def.WebServices {
+		buff := bytes.NewBufferString(markdownMarker + "\n\n")
+		if err := markdownTemplate.ExecuteTemplate(buff, "service", service); err != nil {
+			return nil, fmt.Errorf("failed to render markdown of service %s：%w", service.ServiceName(), err)
+		}
+		files[service.Name()+markdownFileExt] = buff.Bytes()
+	}
+
+	buff := bytes.NewBufferString(markdownMarker + "\n\n")
+	if err := markdownTemplate.ExecuteTemplate(buff, "index", def); err != nil {
+		return nil, fmt.Errorf("failed to render markdown index：%w", err)
+	}
+	files[markdownIndexFileName] = buff.Bytes()
+
+	return files, nil
+}
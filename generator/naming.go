@@ -0,0 +1,185 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// commonInitialisms are written in upper case in Go identifiers, see https://go.dev/wiki/CodeReviewComments#initialisms
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true, "GUID": true,
+	"HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true, "LHS": true, "QPS": true,
+	"RAM": true, "RHS": true, "RPC": true, "SLA": true, "SMTP": true, "SQL": true, "SSH": true, "TCP": true,
+	"TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true, "UUID": true, "URI": true, "URL": true,
+	"UTF8": true, "VM": true, "XML": true, "XMPP": true, "XSRF": true, "XSS": true,
+}
+
+// reserved names of generated methods (including the ones promoted from the common client of versioned packages),
+// which can't be used for generated fields and methods
+var (
+	reservedClientNames  = map[string]bool{"SetServerVersion": true, "Client": true, "Invoke": true, "ServerVersion": true, "UseDeprecatedKeys": true, "InvokeMultipart": true}
+	reservedRequestNames = map[string]bool{"EncodeValues": true}
+)
+
+// splitWords splits the string into words on non alphanumeric characters and case changes,
+// e.g. "user.id", "user_id", "userId" and "UserID" are split into "user" and "id"
+func splitWords(str string) []string {
+	words := make([]string, 0)
+	runes := []rune(str)
+	start := -1
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+			}
+			start = -1
+			continue
+		}
+		if start >= 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || unicode.IsUpper(prev) && nextLower {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// goName converts the string to a valid Go identifier in camel case with common initialisms in upper case,
+// identifiers which can't start with the first character are prefixed with X
+func goName(str string, exported bool) string {
+	words := splitWords(str)
+	name := new(strings.Builder)
+	for i, word := range words {
+		upper := strings.ToUpper(word)
+		switch {
+		case i == 0 && !exported:
+			name.WriteString(strings.ToLower(word))
+		case commonInitialisms[upper]:
+			name.WriteString(upper)
+		default:
+			runes := []rune(word)
+			name.WriteString(string(unicode.ToUpper(runes[0])) + string(runes[1:]))
+		}
+	}
+	result := name.String()
+	if result == "" || unicode.IsDigit([]rune(result)[0]) {
+		prefix := "X"
+		if !exported {
+			prefix = "x"
+		}
+		result = prefix + result
+	}
+	if token.IsKeyword(result) {
+		result += "_"
+	}
+	return result
+}
+
+func makeExported(str string) string {
+	if str == "" {
+		return str
+	}
+	runes := []rune(str)
+	return string(unicode.ToUpper(runes[0])) + string(runes[1:])
+}
+
+// makeUnexported lowers the first word of the identifier, e.g. UIService becomes uiService
+func makeUnexported(str string) string {
+	words := splitWords(str)
+	if len(words) == 0 || !strings.HasPrefix(str, words[0]) {
+		return str
+	}
+	return strings.ToLower(words[0]) + strings.TrimPrefix(str, words[0])
+}
+
+// LoadRenames reads the rename map from a json file, keys are service paths (api/projects),
+// action paths (api/projects/search) or params (api/projects/search?ps), values are Go names
+func LoadRenames(path string) (map[string]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rename map：%w", err)
+	}
+	renames := make(map[string]string)
+	if err := json.Unmarshal(raw, &renames); err != nil {
+		return nil, fmt.Errorf("failed to decode rename map (%s)：%w", path, err)
+	}
+	for key, name := range renames {
+		if !token.IsIdentifier(name) || !token.IsExported(name) {
+			return nil, fmt.Errorf("invalid name %q of %s in rename map, it must be an exported Go identifier", name, key)
+		}
+	}
+	return renames, nil
+}
+
+// nameDefinition assigns Go names to services, actions and params applying the rename map,
+// colliding names get a numeric suffix and are reported as warnings
+func nameDefinition(def *APIDefinition, renames map[string]string) {
+	getters := newNameSet(reservedClientNames)
+	for _, service := range def.WebServices {
+		name, ok := renames[service.Path]
+		if !ok {
+			name = goName(service.Name(), true)
+		}
+		service.getter = getters.add(name, service.Path, &def.warnings)
+
+		methods := newNameSet(nil)
+		for _, action := range service.Actions {
+			path := service.Path + "/" + action.Key
+			name, ok := renames[path]
+			if !ok {
+				name = goName(action.Key, true)
+			}
+			action.ServiceName = service.ServiceName()
+			action.methodName = methods.add(name, path, &def.warnings)
+
+			fields := newNameSet(reservedRequestNames)
+			for _, p := range action.Params {
+				name, ok := renames[path+"?"+p.Key]
+				if !ok {
+					name = goName(p.Key, true)
+				}
+				p.name = fields.add(name, path+"?"+p.Key, &def.warnings)
+			}
+		}
+	}
+}
+
+type nameSet map[string]bool
+
+func newNameSet(reserved map[string]bool) nameSet {
+	set := make(nameSet, len(reserved))
+	for name := range reserved {
+		set[name] = true
+	}
+	return set
+}
+
+// add returns the name, or the name with the first free numeric suffix if it's already taken
+func (s nameSet) add(name, key string, warnings *[]string) string {
+	result := name
+	for i := 2; s[result]; i++ {
+		result = name + strconv.Itoa(i)
+	}
+	if result != name {
+		*warnings = append(*warnings, fmt.Sprintf("name %s of %s collides with another name, %s is used instead", name, key, result))
+	}
+	s[result] = true
+	return result
+}
@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_goName(t *testing.T) {
+	tests := []struct {
+		name     string
+		str      string
+		exported bool
+		want     string
+	}{
+		{name: "should convert snake case", str: "project_key", exported: true, want: "ProjectKey"},
+		{name: "should convert dotted keys", str: "user.id", exported: true, want: "UserID"},
+		{name: "should upper case initialisms", str: "projectUrl", exported: true, want: "ProjectURL"},
+		{name: "should lower the first word", str: "UIService", want: "uiService"},
+		{name: "should prefix names starting with a digit", str: "2fa-code", exported: true, want: "X2faCode"},
+		{name: "should suffix keywords", str: "func", want: "func_"},
+		{name: "should name empty strings", str: "-", exported: true, want: "X"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := goName(tt.str, tt.exported); got != tt.want {
+				t.Errorf("goName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_nameDefinition(t *testing.T) {
+	def := &APIDefinition{
+		WebServices: []*WebService{
+			{Path: "api/projects", Actions: []*Action{
+				{Key: "search", Params: []*Param{{Key: "projectId"}, {Key: "project_id"}, {Key: "ps"}}},
+			}},
+			{Path: "api/set_server_version"},
+		},
+	}
+	nameDefinition(def, map[string]string{"api/projects/search?ps": "PageSize"})
+
+	action := def.WebServices[0].Actions[0]
+	got := []string{def.WebServices[0].Getter(), def.WebServices[1].Getter(), action.MethodName(), action.ServiceName}
+	for _, p := range action.Params {
+		got = append(got, p.ParamName())
+	}
+	want := []string{"Projects", "SetServerVersion2", "Search", "ProjectsService", "ProjectID", "ProjectID2", "PageSize"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("nameDefinition() names = %v, want %v", got, want)
+	}
+	if len(def.warnings) != 2 {
+		t.Errorf("nameDefinition() warnings = %v, want 2 warnings", def.warnings)
+	}
+}
@@ -0,0 +1,374 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	openAPIVersion  = "3.1.0"
+	openAPIFileName = "openapi.json"
+	formContentType = "application/x-www-form-urlencoded"
+	// multipartContentType is the content type of requests uploading files
+	multipartContentType = "multipart/form-data"
+	fileContentType      = "application/octet-stream"
+)
+
+type openAPIDocument struct {
+	OpenAPI string                      `json:"openapi"`
+	Info    openAPIInfo                 `json:"info"`
+	Servers []openAPIServer             `json:"servers,omitempty"`
+	Tags    []openAPITag                `json:"tags,omitempty"`
+	Paths   map[string]*openAPIPathItem `json:"paths"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIServer struct {
+	URL string `json:"url"`
+}
+
+type openAPITag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type openAPIPathItem struct {
+	Get  *openAPIOperation `json:"get,omitempty"`
+	Post *openAPIOperation `json:"post,omitempty"`
+}
+
+type openAPIOperation struct {
+	OperationID     string                      `json:"operationId"`
+	Description     string                      `json:"description,omitempty"`
+	Tags            []string                    `json:"tags"`
+	Deprecated      bool                        `json:"deprecated,omitempty"`
+	Parameters      []*openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody     *openAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses       map[string]*openAPIResponse `json:"responses"`
+	Since           string                      `json:"x-since,omitempty"`
+	DeprecatedSince string                      `json:"x-deprecated-since,omitempty"`
+	Internal        bool                        `json:"x-internal,omitempty"`
+	Changelog       []*openAPIChange            `json:"x-changelog,omitempty"`
+}
+
+type openAPIChange struct {
+	Version     string `json:"version"`
+	Description string `json:"description"`
+}
+
+type openAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Deprecated  bool           `json:"deprecated,omitempty"`
+	Style       string         `json:"style,omitempty"`
+	Explode     *bool          `json:"explode,omitempty"`
+	Schema      *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                         `json:"required,omitempty"`
+	Content  map[string]*openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema   *openAPISchema              `json:"schema,omitempty"`
+	Example  interface{}                 `json:"example,omitempty"`
+	Encoding map[string]*openAPIEncoding `json:"encoding,omitempty"`
+}
+
+type openAPIEncoding struct {
+	Style   string `json:"style,omitempty"`
+	Explode *bool  `json:"explode,omitempty"`
+}
+
+type openAPISchema struct {
+	Type               string                    `json:"type,omitempty"`
+	ContentMediaType   string                    `json:"contentMediaType,omitempty"`
+	Description        string                    `json:"description,omitempty"`
+	Enum               []string                  `json:"enum,omitempty"`
+	Default            interface{}               `json:"default,omitempty"`
+	Examples           []string                  `json:"examples,omitempty"`
+	Maximum            *int                      `json:"maximum,omitempty"`
+	MinLength          *int                      `json:"minLength,omitempty"`
+	MaxLength          *int                      `json:"maxLength,omitempty"`
+	MaxItems           *int                      `json:"maxItems,omitempty"`
+	Items              *openAPISchema            `json:"items,omitempty"`
+	Properties         map[string]*openAPISchema `json:"properties,omitempty"`
+	Required           []string                  `json:"required,omitempty"`
+	Deprecated         bool                      `json:"deprecated,omitempty"`
+	Since              string                    `json:"x-since,omitempty"`
+	DeprecatedKey      string                    `json:"x-deprecated-key,omitempty"`
+	DeprecatedKeySince string                    `json:"x-deprecated-key-since,omitempty"`
+	Internal           bool                      `json:"x-internal,omitempty"`
+}
+
+// renderOpenAPI writes the api definition as an OpenAPI 3.1 json document
+func renderOpenAPI(in io.Writer, data *APIDefinition) error {
+	raw, err := json.MarshalIndent(newOpenAPIDocument(data), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to render openapi document：%w", err)
+	}
+	_, err = in.Write(append(raw, '\n'))
+	return err
+}
+
+func newOpenAPIDocument(def *APIDefinition) *openAPIDocument {
+	doc := &openAPIDocument{
+		OpenAPI: openAPIVersion,
+		Info: openAPIInfo{
+			Title:   "SonarQube Web API",
+			Version: def.Version.String(),
+		},
+		Paths: make(map[string]*openAPIPathItem),
+	}
+	if def.Host != "" {
+		doc.Servers = []openAPIServer{{URL: def.Host}}
+	}
+
+	for _, service := range def.WebServices {
+		doc.Tags = append(doc.Tags, openAPITag{
+			Name:        service.Name(),
+			Description: plainText(service.Description),
+		})
+		for _, action := range service.Actions {
+			item := &openAPIPathItem{}
+			if action.Post {
+				item.Post = newOpenAPIOperation(service, action)
+			} else {
+				item.Get = newOpenAPIOperation(service, action)
+			}
+			doc.Paths["/"+service.Path+"/"+action.Key] = item
+		}
+	}
+	return doc
+}
+
+func newOpenAPIOperation(service *WebService, action *Action) *openAPIOperation {
+	op := &openAPIOperation{
+		OperationID: makeUnexported(service.Getter() + action.MethodName()),
+		Description: plainText(action.Description),
+		Tags:        []string{service.Name()},
+		Deprecated:  action.Deprecated(),
+		Since:       action.Since.String(),
+		Internal:    action.Internal,
+		Responses: map[string]*openAPIResponse{
+			"200": newOpenAPIResponse(action),
+		},
+	}
+	if action.Deprecated() {
+		op.DeprecatedSince = action.DeprecatedSince.String()
+	}
+	for _, c := range action.Changelog {
+		op.Changelog = append(op.Changelog, &openAPIChange{Version: c.Version, Description: plainText(c.Description)})
+	}
+
+	if !action.Post {
+		for _, p := range action.Params {
+			parameter := &openAPIParameter{
+				Name:        p.Key,
+				In:          "query",
+				Description: plainText(p.Description),
+				Required:    p.Required,
+				Deprecated:  p.Deprecated(),
+				Schema:      newOpenAPIParamSchema(p),
+			}
+			if p.IsList() {
+				parameter.Style, parameter.Explode = "form", new(bool)
+			}
+			op.Parameters = append(op.Parameters, parameter)
+		}
+		return op
+	}
+
+	if len(action.Params) == 0 {
+		return op
+	}
+	schema := &openAPISchema{
+		Type:       "object",
+		Properties: make(map[string]*openAPISchema, len(action.Params)),
+	}
+	encoding := make(map[string]*openAPIEncoding)
+	for _, p := range action.Params {
+		property := newOpenAPIParamSchema(p)
+		property.Description = plainText(p.Description)
+		property.Deprecated = p.Deprecated()
+		schema.Properties[p.Key] = property
+		if p.Required {
+			schema.Required = append(schema.Required, p.Key)
+		}
+		if p.IsList() {
+			encoding[p.Key] = &openAPIEncoding{Style: "form", Explode: new(bool)}
+		}
+	}
+	media := &openAPIMediaType{Schema: schema}
+	if len(encoding) != 0 {
+		media.Encoding = encoding
+	}
+	contentType := formContentType
+	if action.HasFiles() {
+		contentType = multipartContentType
+	}
+	op.RequestBody = &openAPIRequestBody{
+		Required: len(schema.Required) != 0,
+		Content:  map[string]*openAPIMediaType{contentType: media},
+	}
+	return op
+}
+
+func newOpenAPIParamSchema(p *Param) *openAPISchema {
+	schema := &openAPISchema{Type: "string"}
+	if p.ExampleValue != "" {
+		schema.Examples = []string{p.ExampleValue}
+	}
+	schema.Since = p.Since.String()
+	if p.DeprecatedKey != "" {
+		schema.DeprecatedKey = p.DeprecatedKey
+		schema.DeprecatedKeySince = p.DeprecatedKeySince.String()
+	}
+	schema.Internal = p.Internal
+
+	var def interface{}
+	if p.DefaultValue != "" {
+		def = p.DefaultValue
+	}
+	switch {
+	case p.IsFile():
+		schema.ContentMediaType = fileContentType
+	case p.IsBool() && !p.yesNo() && len(p.PossibleValues) == 2:
+		schema.Type = "boolean"
+		if b, err := strconv.ParseBool(p.DefaultValue); err == nil {
+			def = b
+		}
+	case p.MaximumValue != 0:
+		schema.Type = "integer"
+		schema.Maximum = intPtr(p.MaximumValue)
+		if i, err := strconv.Atoi(p.DefaultValue); err == nil {
+			def = i
+		}
+	default:
+		schema.Enum = p.PossibleValues
+	}
+	if p.MinimumLength != 0 {
+		schema.MinLength = intPtr(p.MinimumLength)
+	}
+	if p.MaximumLength != 0 {
+		schema.MaxLength = intPtr(p.MaximumLength)
+	}
+
+	if !p.IsList() {
+		schema.Default = def
+		return schema
+	}
+	if p.DefaultValue != "" {
+		def = strings.Split(p.DefaultValue, ",")
+	}
+	list := &openAPISchema{
+		Type:               "array",
+		Items:              schema,
+		MaxItems:           intPtr(p.MaxValuesAllowed),
+		Default:            def,
+		Since:              schema.Since,
+		DeprecatedKey:      schema.DeprecatedKey,
+		DeprecatedKeySince: schema.DeprecatedKeySince,
+		Internal:           schema.Internal,
+	}
+	schema.Since, schema.DeprecatedKey, schema.DeprecatedKeySince, schema.Internal = "", "", "", false
+	return list
+}
+
+func newOpenAPIResponse(action *Action) *openAPIResponse {
+	response := &openAPIResponse{Description: "Successful response"}
+	example := action.ResponseExample
+	if example == nil && action.IsStream() {
+		response.Content = map[string]*openAPIMediaType{
+			fileContentType: {Schema: &openAPISchema{Type: "string", ContentMediaType: fileContentType}},
+		}
+	}
+	if example == nil {
+		return response
+	}
+	media := &openAPIMediaType{}
+	if example.Format == "json" {
+		var value interface{}
+		if err := json.Unmarshal([]byte(example.Example), &value); err == nil {
+			media.Schema = schemaFromExample(value)
+			media.Example = value
+		}
+	}
+	if media.Schema == nil {
+		media.Schema = &openAPISchema{Type: "string"}
+		media.Example = example.Example
+	}
+	response.Content = map[string]*openAPIMediaType{
+		exampleContentType(example.Format): media,
+	}
+	return response
+}
+
+// schemaFromExample infers the schema of the json value, array items are inferred from the first element
+func schemaFromExample(value interface{}) *openAPISchema {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		schema := &openAPISchema{
+			Type:       "object",
+			Properties: make(map[string]*openAPISchema, len(v)),
+		}
+		for key, item := range v {
+			schema.Properties[key] = schemaFromExample(item)
+		}
+		return schema
+	case []interface{}:
+		schema := &openAPISchema{Type: "array", Items: &openAPISchema{}}
+		if len(v) != 0 {
+			schema.Items = schemaFromExample(v[0])
+		}
+		return schema
+	case string:
+		return &openAPISchema{Type: "string"}
+	case float64:
+		if v == float64(int64(v)) {
+			return &openAPISchema{Type: "integer"}
+		}
+		return &openAPISchema{Type: "number"}
+	case bool:
+		return &openAPISchema{Type: "boolean"}
+	default:
+		return &openAPISchema{}
+	}
+}
+
+// exampleContentType maps the format of a response example to the content type
+func exampleContentType(format string) string {
+	switch format {
+	case "json":
+		return "application/json"
+	case "xml":
+		return "application/xml"
+	case "svg":
+		return "image/svg+xml"
+	case "proto":
+		return "application/x-protobuf"
+	case "txt", "text", "log":
+		return "text/plain"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
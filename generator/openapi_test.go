@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_newOpenAPIDocument(t *testing.T) {
+	def := createAPIDefinition(
+		apiDefinitionWithWebServices(
+			createWebService(
+				func(ws *WebService) { ws.Path = "api/projects" },
+				webServiceWithActions(
+					createAction(func(a *Action) {
+						a.Key = "search"
+						a.Params = []*Param{
+							createParam(func(p *Param) {
+								p.Key = "qualifiers"
+								p.PossibleValues = []string{"TRK", "VW"}
+								p.MaxValuesAllowed = 2
+								p.DefaultValue = "TRK"
+							}),
+							createParam(func(p *Param) {
+								p.Key = "ps"
+								p.MaximumValue = 500
+								p.DefaultValue = "100"
+							}),
+						}
+					}),
+					createAction(func(a *Action) {
+						a.Key = "create"
+						a.Post = true
+						a.Params = []*Param{createParam(func(p *Param) {
+							p.Key = "name"
+							p.Required = true
+						})}
+					}),
+				),
+			),
+		),
+	)
+
+	doc := newOpenAPIDocument(def)
+
+	search := doc.Paths["/api/projects/search"]
+	if search == nil || search.Get == nil || search.Post != nil {
+		t.Fatalf("newOpenAPIDocument() search path = %+v, want GET operation", search)
+	}
+	if search.Get.OperationID != "projectsSearch" {
+		t.Errorf("newOpenAPIDocument() operationId = %v, want projectsSearch", search.Get.OperationID)
+	}
+	qualifiers := search.Get.Parameters[0].Schema
+	if qualifiers.Type != "array" || *qualifiers.MaxItems != 2 || !reflect.DeepEqual(qualifiers.Items.Enum, []string{"TRK", "VW"}) ||
+		!reflect.DeepEqual(qualifiers.Default, []string{"TRK"}) {
+		t.Errorf("newOpenAPIDocument() list param schema = %+v", qualifiers)
+	}
+	ps := search.Get.Parameters[1].Schema
+	if ps.Type != "integer" || *ps.Maximum != 500 || ps.Default != 100 {
+		t.Errorf("newOpenAPIDocument() integer param schema = %+v", ps)
+	}
+
+	create := doc.Paths["/api/projects/create"]
+	if create == nil || create.Post == nil || create.Post.RequestBody == nil {
+		t.Fatalf("newOpenAPIDocument() create path = %+v, want POST operation with body", create)
+	}
+	body := create.Post.RequestBody.Content[formContentType].Schema
+	if !reflect.DeepEqual(body.Required, []string{"name"}) || body.Properties["name"] == nil {
+		t.Errorf("newOpenAPIDocument() request body schema = %+v", body)
+	}
+}
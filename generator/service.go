@@ -0,0 +1,40 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+)
+
+const (
+	serviceTemplateName = "service.tpl"
+)
+
+func (g *Generator) renderService(in io.Writer, data *WebService) error {
+
+	buff := bytes.NewBuffer([]byte{})
+	buff.WriteString(generatedMarker + "\n\n")
+
+	serviceTemplate, err := g.parseTemplate(serviceTemplateName)
+	if err != nil {
+		return err
+	}
+
+	if err := serviceTemplate.Execute(buff, data); err != nil {
+		return fmt.Errorf("failed to render service %s：%w", data.ServiceName(), err)
+	}
+
+	src := moveDocLinks(buff.Bytes())
+
+	formatted, err := format.Source(src)
+	if err != nil {
+		log.Printf("failed to format source of %s, %s", data.ServiceName(), err.Error())
+		formatted = src
+	}
+
+	_, err = in.Write(formatted)
+
+	return err
+}
@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"runtime/debug"
+	"strings"
+)
+
+const (
+	modulePath = "github.com/RidgeA/sonarqube-api-client-gen"
+	// develVersion is the version of the generator built from its source tree
+	develVersion = "(devel)"
+	// hashLength is the number of hex digits of the definition hash written to the header
+	hashLength = 16
+)
+
+// buildVersion returns the version of the generator module, it's known if the tool is installed with
+// go install or the module is a dependency
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return develVersion
+	}
+	module := &info.Main
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			module = dep
+		}
+	}
+	if module.Replace != nil {
+		module = module.Replace
+	}
+	if module.Path != modulePath || module.Version == "" {
+		return develVersion
+	}
+	return module.Version
+}
+
+// definitionHash returns the hash of the raw api definition
+func definitionHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// stamp returns the header line describing how the files were generated,
+// it's written right after the generated marker, so regenerations can be compared
+func (g *Generator) stamp(defs ...*APIDefinition) string {
+	versions := make([]string, 0, len(defs))
+	hashes := make([]string, 0, len(defs))
+	for _, def := range defs {
+		versions = append(versions, def.Version.String())
+		if def.hash != "" {
+			hashes = append(hashes, def.hash[:hashLength])
+		}
+	}
+	stamp := fmt.Sprintf("sonarqube-api-client-gen %s, SonarQube %s", g.opts.GeneratorVersion, strings.Join(versions, ", "))
+	if len(hashes) != 0 {
+		stamp += ", definition sha256:" + strings.Join(hashes, ", ")
+	}
+	return stamp
+}
+
+// stampFiles writes the stamp after the generated marker of go and markdown files
+func stampFiles(files map[string][]byte, stamp string) {
+	for name, content := range files {
+		switch {
+		case bytes.HasPrefix(content, []byte(generatedMarker+"\n")):
+			files[name] = insertAfterLine(content, "// "+stamp+"\n")
+		case bytes.HasPrefix(content, []byte(markdownMarker+"\n")):
+			files[name] = insertAfterLine(content, "<!-- "+stamp+" -->\n")
+		}
+	}
+}
+
+func insertAfterLine(content []byte, line string) []byte {
+	end := bytes.IndexByte(content, '\n') + 1
+	result := make([]byte, 0, len(content)+len(line))
+	result = append(result, content[:end]...)
+	result = append(result, line...)
+	return append(result, content[end:]...)
+}
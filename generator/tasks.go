@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+)
+
+const (
+	ceServicePath      = "api/ce"
+	ceTaskTemplateName = "ce-task.tpl"
+	ceTaskFileName     = "ce_task.go"
+)
+
+// ceTaskData is passed to the ce task template, the waiter is built on the task action of the ce service
+type ceTaskData struct {
+	PackageName string
+	Service     *WebService
+	Task        *Action
+	ID          *Param
+}
+
+// findAction returns the action of the service, or nil if the definition doesn't have it
+func findAction(def *APIDefinition, path, key string) (*WebService, *Action) {
+	for _, service := range def.WebServices {
+		if service.Path != path {
+			continue
+		}
+		for _, action := range service.Actions {
+			if action.Key == key {
+				return service, action
+			}
+		}
+	}
+	return nil, nil
+}
+
+// findParam returns the param of the action, or nil if the action doesn't have it
+func findParam(action *Action, key string) *Param {
+	for _, p := range action.Params {
+		if p.Key == key {
+			return p
+		}
+	}
+	return nil
+}
+
+// stringParam returns the param of the action if it's a *string field, or nil otherwise
+func stringParam(action *Action, key string) *Param {
+	p := findParam(action, key)
+	if p == nil || p.GoType() != "*string" {
+		return nil
+	}
+	return p
+}
+
+// newCETaskData returns the data of the ce task waiter, or nil if the definition lacks api/ce/task
+func newCETaskData(def *APIDefinition) *ceTaskData {
+	service, task := findAction(def, ceServicePath, "task")
+	if task == nil || task.IsStream() {
+		return nil
+	}
+	id := stringParam(task, "id")
+	if id == nil {
+		return nil
+	}
+	return &ceTaskData{PackageName: def.PackageName, Service: service, Task: task, ID: id}
+}
+
+// renderHelper renders a template of hand-written like helpers built on generated services
+func (g *Generator) renderHelper(in io.Writer, templateName string, data interface{}) error {
+
+	buff := bytes.NewBuffer([]byte{})
+	buff.WriteString(generatedMarker + "\n\n")
+
+	helperTemplate, err := g.parseTemplate(templateName)
+	if err != nil {
+		return err
+	}
+
+	if err := helperTemplate.Execute(buff, data); err != nil {
+		return fmt.Errorf("failed to render helper %s：%w", templateName, err)
+	}
+
+	src := buff.Bytes()
+
+	formatted, err := format.Source(src)
+	if err != nil {
+		log.Printf("failed to format source of helper %s: err:%s", templateName, err.Error())
+		formatted = src
+	}
+
+	_, err = in.Write(formatted)
+	return err
+}
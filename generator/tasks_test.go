@@ -0,0 +1,159 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_newCETaskData(t *testing.T) {
+	newDef := func(params ...*Param) *APIDefinition {
+		return &APIDefinition{WebServices: []*WebService{
+			{Path: "api/ce", Actions: []*Action{{Key: "activity"}, {Key: "task", Params: params}}},
+		}}
+	}
+	tests := []struct {
+		name string
+		def  *APIDefinition
+		want bool
+	}{
+		{name: "should build the waiter on api/ce/task", def: newDef(&Param{Key: "id"}, &Param{Key: "additionalFields"}), want: true},
+		{name: "should skip task without id", def: newDef(&Param{Key: "taskId"})},
+		{name: "should skip definition without ce", def: &APIDefinition{WebServices: []*WebService{{Path: "api/projects"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newCETaskData(tt.def)
+			if (got != nil) != tt.want {
+				t.Errorf("newCETaskData() = %v, want data %v", got, tt.want)
+			}
+			if got != nil && (got.Task.Key != "task" || got.ID.Key != "id") {
+				t.Errorf("newCETaskData() task = %s, id = %s", got.Task.Key, got.ID.Key)
+			}
+		})
+	}
+}
+
+const ceTaskSnapshot = `{"webServices": [{"path": "api/ce", "description": "Get information on Compute Engine tasks.",
+	"actions": [{"key": "task", "description": "Give Compute Engine task details", "since": "5.2", "post": false,
+		"params": [{"key": "id", "description": "Id of task", "required": true}]}]}]}`
+
+// ceTaskTest runs in the generated package, the server returns the statuses one per poll
+// and blocks on the status SLOW until the request is canceled
+const ceTaskTest = `package sonar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func serveTask(t *testing.T, statuses ...string) (*Client, func() []time.Time) {
+	mu := sync.Mutex{}
+	var polls []time.Time
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		status := statuses[len(polls)]
+		polls = append(polls, time.Now())
+		mu.Unlock()
+		if status == "SLOW" {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(10 * time.Second):
+			}
+			return
+		}
+		fmt.Fprintf(w, ` + "`" + `{"task": {"id": %q, "status": %q, "errorMessage": "broken"}}` + "`" + `, r.URL.Query().Get("id"), status)
+	}))
+	t.Cleanup(ts.Close)
+	return NewClient(ts.Client(), ts.URL, "", ""), func() []time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return polls
+	}
+}
+
+func TestWaitForTaskBackoff(t *testing.T) {
+	client, polls := serveTask(t, "PENDING", "IN_PROGRESS", "IN_PROGRESS", "SUCCESS")
+	task, err := client.Ce().WaitForTask(context.Background(), "AX1", &WaitOptions{
+		Interval: 20 * time.Millisecond, Backoff: 2, MaxInterval: 50 * time.Millisecond,
+	})
+	if err != nil || task.ID != "AX1" || task.Status != TaskSuccess {
+		t.Fatalf("WaitForTask() = %v, %v, want successful task AX1", task, err)
+	}
+	got := polls()
+	if len(got) != 4 {
+		t.Fatalf("WaitForTask() polled %d times, want 4", len(got))
+	}
+	for i, want := range []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond} {
+		if delay := got[i+1].Sub(got[i]); delay < want {
+			t.Errorf("WaitForTask() delay before poll %d = %v, want at least %v", i+2, delay, want)
+		}
+	}
+}
+
+func TestWaitForTaskFailed(t *testing.T) {
+	client, _ := serveTask(t, "PENDING", "FAILED")
+	task, err := client.Ce().WaitForTask(context.Background(), "AX1", &WaitOptions{Interval: time.Millisecond})
+	taskErr := &TaskError{}
+	if !errors.As(err, &taskErr) || taskErr.Task != task || task.Status != TaskFailed || task.ErrorMessage != "broken" {
+		t.Errorf("WaitForTask() = %v, %v, want *TaskError of failed task", task, err)
+	}
+}
+
+func TestWaitForTaskNeverDone(t *testing.T) {
+	statuses := make([]string, 1000)
+	for i := range statuses {
+		statuses[i] = "PENDING"
+	}
+	client, _ := serveTask(t, statuses...)
+	start := time.Now()
+	task, err := client.Ce().WaitForTask(context.Background(), "AX1", &WaitOptions{
+		Interval: 10 * time.Millisecond, Timeout: 100 * time.Millisecond,
+	})
+	if !errors.Is(err, context.DeadlineExceeded) || task == nil || task.Status != TaskPending {
+		t.Errorf("WaitForTask() = %v, %v, want pending task with deadline exceeded", task, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("WaitForTask() returned after %v, want the timeout of 100ms", elapsed)
+	}
+}
+
+func TestWaitForTaskTimeout(t *testing.T) {
+	client, _ := serveTask(t, "PENDING", "SLOW")
+	start := time.Now()
+	_, err := client.Ce().WaitForTask(context.Background(), "AX1", &WaitOptions{
+		Interval: time.Millisecond, Timeout: 100 * time.Millisecond,
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitForTask() error = %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("WaitForTask() returned after %v, the slow request ignored the timeout", elapsed)
+	}
+}
+`
+
+func Test_Generator_Run_helperCollision(t *testing.T) {
+	snapshot := filepath.Join(t.TempDir(), "snapshot.json")
+	collision := strings.Replace(ceTaskSnapshot, `[{"path": "api/ce"`, `[{"path": "api/ce_task", "description": "Tasks.",
+		"actions": [{"key": "list", "description": "List tasks", "since": "5.2", "post": false, "params": []}]}, {"path": "api/ce"`, 1)
+	if err := os.WriteFile(snapshot, []byte(collision), 0644); err != nil {
+		t.Fatal(err)
+	}
+	g := NewGenerator(Options{PackageName: "sonar", Out: t.TempDir(), Mode: ModeDryRun})
+	_, err := g.Run([]*Target{{Version: "7.1", Snapshot: snapshot}})
+	if err == nil || !strings.Contains(err.Error(), ceTaskFileName) {
+		t.Errorf("Run() error = %v, want collision of %s", err, ceTaskFileName)
+	}
+}
+
+func Test_WaitForTask(t *testing.T) {
+	testGenerated(t, Options{}, ceTaskSnapshot, ceTaskTest)
+}
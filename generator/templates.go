@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"text/template"
+)
+
+// builtinTemplates are used if the template dir isn't set, so the generator works outside of its source tree,
+// partials are listed explicitly because embedding a dir skips files starting with _
+//
+//go:embed tpl tpl/_*.tpl
+var builtinTemplates embed.FS
+
+// templateFS returns the template dir, or the built-in templates if it's empty
+func templateFS(dir string) fs.FS {
+	if dir != "" {
+		return os.DirFS(dir)
+	}
+	templates, err := fs.Sub(builtinTemplates, "tpl")
+	if err != nil {
+		panic(err)
+	}
+	return templates
+}
+
+// partialPattern matches helper templates of the template dir, they are parsed together with every template,
+// so the templates they define can be used as functions with include, e.g. {{include "fieldName" .}}
+const partialPattern = "_*.tpl"
+
+// parseTemplate returns the template of the template dir parsed with the partials of the dir,
+// every template is parsed once per generator and shared by the renderings
+func (g *Generator) parseTemplate(name string) (*template.Template, error) {
+	g.parsedMu.Lock()
+	defer g.parsedMu.Unlock()
+	if t, ok := g.parsed[name]; ok {
+		return t, nil
+	}
+	t, err := g.parseTemplateFS(name)
+	if err != nil {
+		return nil, err
+	}
+	g.parsed[name] = t
+	return t, nil
+}
+
+// parseTemplateFS parses the template of the template dir with the partials of the dir
+func (g *Generator) parseTemplateFS(name string) (*template.Template, error) {
+	partials, err := fs.Glob(g.templates, partialPattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find partial templates：%w", err)
+	}
+	files := append([]string{name}, partials...)
+
+	t := template.New(name).Funcs(g.helpers)
+	if t, err = t.ParseFS(g.templates, files...); err != nil {
+		return nil, fmt.Errorf("failed to parse template %s：%w", name, err)
+	}
+	return t.Funcs(template.FuncMap{"include": include(t)}), nil
+}
+
+// include returns the function rendering the named template of t into a string, so it can be piped to other functions
+func include(t *template.Template) func(name string, data interface{}) (string, error) {
+	return func(name string, data interface{}) (string, error) {
+		buff := new(bytes.Buffer)
+		if err := t.ExecuteTemplate(buff, name, data); err != nil {
+			return "", err
+		}
+		return buff.String(), nil
+	}
+}
@@ -0,0 +1,58 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_parseTemplate(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"_names.tpl": `{{define "field"}}{{.Key | pascal}}{{end}}`,
+		"test.tpl":   `{{include "field" . | quote}} {{template "field" .}} {{.Key | kebab}} {{.Kind}}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g := NewGenerator(Options{TemplateDir: dir})
+	tpl, err := g.parseTemplate("test.tpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, err := g.parseTemplate("test.tpl"); err != nil || again != tpl {
+		t.Errorf("parseTemplate() parsed the template again = %v, %v", again, err)
+	}
+	result := new(strings.Builder)
+	if err := tpl.Execute(result, &Param{Key: "projectKey", PossibleValues: []string{"a", "b"}}); err != nil {
+		t.Fatal(err)
+	}
+	if want := `"ProjectKey" ProjectKey project-key enum`; result.String() != want {
+		t.Errorf("got %q, want %q", result.String(), want)
+	}
+}
+
+func Test_caseHelpers(t *testing.T) {
+	tests := []struct {
+		in, camel, pascal, snake, kebab string
+	}{
+		{"projectKey", "projectKey", "ProjectKey", "project_key", "project-key"},
+		{"project_key", "projectKey", "ProjectKey", "project_key", "project-key"},
+		{"ps", "ps", "Ps", "ps", "ps"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got := []string{camel(tt.in), pascal(tt.in), snake(tt.in), kebab(tt.in)}
+			want := []string{tt.camel, tt.pascal, tt.snake, tt.kebab}
+			for i := range got {
+				if got[i] != want[i] {
+					t.Errorf("got %v, want %v", got, want)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"log"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// templateSetFileName is the manifest of the template dir declaring additional templates, e.g. tests, mocks or docs
+const templateSetFileName = "templates.json"
+
+// scopes of templates, a template is rendered once for the definition, for every service or for every action
+const (
+	scopeDefinition = "definition"
+	scopeService    = "service"
+	scopeAction     = "action"
+)
+
+// templateSpec is an entry of the template set manifest
+type templateSpec struct {
+	// Template is the name of the template file in the template dir
+	Template string `json:"template"`
+	// Scope is definition, service or action
+	Scope string `json:"scope"`
+	// Output is the template of the output file path relative to the package dir, it's rendered with the same data
+	Output string `json:"output"`
+
+	output *template.Template
+}
+
+// loadTemplateSet reads the template set manifest of the template dir, it's optional
+func (g *Generator) loadTemplateSet() ([]*templateSpec, error) {
+	manifest := templateSetFileName
+	raw, err := fs.ReadFile(g.templates, manifest)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template set：%w", err)
+	}
+	specs := make([]*templateSpec, 0)
+	if err := json.Unmarshal(raw, &specs); err != nil {
+		return nil, fmt.Errorf("failed to decode template set (%s)：%w", manifest, err)
+	}
+	for i, spec := range specs {
+		if spec.Template == "" || spec.Output == "" {
+			return nil, fmt.Errorf("template and output are required, entry %d of %s", i, manifest)
+		}
+		switch spec.Scope {
+		case scopeDefinition, scopeService, scopeAction:
+		default:
+			return nil, fmt.Errorf("invalid scope %q of %s, it must be %s, %s or %s", spec.Scope, spec.Template, scopeDefinition, scopeService, scopeAction)
+		}
+		if spec.output, err = template.New(spec.Template).Funcs(g.helpers).Parse(spec.Output); err != nil {
+			return nil, fmt.Errorf("failed to parse output of %s：%w", spec.Template, err)
+		}
+	}
+	return specs, nil
+}
+
+// renderTemplateSet renders the templates of the set into files, generated files must not be overwritten
+func (g *Generator) renderTemplateSet(def *APIDefinition, specs []*templateSpec, files map[string][]byte) error {
+	for _, spec := range specs {
+		t, err := g.parseTemplate(spec.Template)
+		if err != nil {
+			return err
+		}
+		for _, data := range scopeData(def, spec.Scope) {
+			name, err := spec.outputName(data)
+			if err != nil {
+				return err
+			}
+			if _, ok := files[name]; ok {
+				return fmt.Errorf("output %s of %s collides with another generated file", name, spec.Template)
+			}
+			content, err := renderArtifact(t, name, data)
+			if err != nil {
+				return err
+			}
+			files[name] = content
+		}
+	}
+	return nil
+}
+
+// scopeData returns the data of every rendering of a template of the scope
+func scopeData(def *APIDefinition, scope string) []interface{} {
+	result := make([]interface{}, 0)
+	switch scope {
+	case scopeDefinition:
+		result = append(result, def)
+	case scopeService:
+		for _, service := range def.WebServices {
+			result = append(result, service)
+		}
+	case scopeAction:
+		for _, service := range def.WebServices {
+			for _, action := range service.Actions {
+				result = append(result, action)
+			}
+		}
+	}
+	return result
+}
+
+// outputName renders the output path, it must stay inside the package dir
+func (spec *templateSpec) outputName(data interface{}) (string, error) {
+	buff := new(bytes.Buffer)
+	if err := spec.output.Execute(buff, data); err != nil {
+		return "", fmt.Errorf("failed to render output of %s：%w", spec.Template, err)
+	}
+	name := strings.TrimSpace(buff.String())
+	if name == "" || !filepath.IsLocal(name) {
+		return "", fmt.Errorf("invalid output %q of %s, it must be a path inside the package dir", name, spec.Template)
+	}
+	return path.Clean(filepath.ToSlash(name)), nil
+}
+
+// renderArtifact renders the file, go sources get the generated marker and are formatted,
+// markdown files get the markdown marker, other files are written as they are rendered
+func renderArtifact(t *template.Template, name string, data interface{}) ([]byte, error) {
+	buff := new(bytes.Buffer)
+	switch path.Ext(name) {
+	case ".go":
+		buff.WriteString(generatedMarker + "\n\n")
+	case ".md":
+		buff.WriteString(markdownMarker + "\n\n")
+	}
+	if err := t.Execute(buff, data); err != nil {
+		return nil, fmt.Errorf("failed to render %s：%w", name, err)
+	}
+	if path.Ext(name) != ".go" {
+		return buff.Bytes(), nil
+	}
+
+	src := moveDocLinks(buff.Bytes())
+	formatted, err := format.Source(src)
+	if err != nil {
+		log.Printf("failed to format source of %s: err:%s", name, err.Error())
+		formatted = src
+	}
+	return formatted, nil
+}
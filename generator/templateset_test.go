@@ -0,0 +1,103 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func Test_renderTemplateSet(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		templateSetFileName: `[
+			{"template": "doc.tpl", "scope": "definition", "output": "docs/index.md"},
+			{"template": "mock.tpl", "scope": "service", "output": "mocks/{{.Name | snake}}_mock.go"},
+			{"template": "doc.tpl", "scope": "action", "output": "docs/{{.Service.Name}}/{{.Key | kebab}}.txt"}
+		]`,
+		"doc.tpl":  `doc`,
+		"mock.tpl": `package mocks`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g := NewGenerator(Options{TemplateDir: dir})
+
+	def := &APIDefinition{WebServices: []*WebService{
+		{Path: "api/user_groups", Actions: []*Action{{Key: "search"}, {Key: "add_user"}}},
+	}}
+	for _, service := range def.WebServices {
+		for _, action := range service.Actions {
+			action.service = service
+		}
+	}
+
+	specs, err := g.loadTemplateSet()
+	if err != nil {
+		t.Fatalf("loadTemplateSet() error = %v", err)
+	}
+	rendered := map[string][]byte{}
+	if err := g.renderTemplateSet(def, specs, rendered); err != nil {
+		t.Fatalf("renderTemplateSet() error = %v", err)
+	}
+	names := make([]string, 0, len(rendered))
+	for name := range rendered {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	want := []string{"docs/index.md", "docs/user_groups/add-user.txt", "docs/user_groups/search.txt", "mocks/user_groups_mock.go"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("renderTemplateSet() = %v, want %v", names, want)
+	}
+	if got := string(rendered["mocks/user_groups_mock.go"]); !strings.HasPrefix(got, generatedMarker) {
+		t.Errorf("renderTemplateSet() go file without generated marker: %q", got)
+	}
+	if got := string(rendered["docs/user_groups/search.txt"]); got != "doc" {
+		t.Errorf("renderTemplateSet() = %q, want %q", got, "doc")
+	}
+
+	if err := g.renderTemplateSet(def, specs, rendered); err == nil {
+		t.Errorf("renderTemplateSet() expected error on colliding outputs")
+	}
+}
+
+func Test_loadTemplateSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+	}{
+		{name: "should require output", manifest: `[{"template": "a.tpl", "scope": "service"}]`},
+		{name: "should validate scope", manifest: `[{"template": "a.tpl", "scope": "param", "output": "a.go"}]`},
+		{name: "should parse output", manifest: `[{"template": "a.tpl", "scope": "service", "output": "{{.Name"}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, templateSetFileName), []byte(tt.manifest), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := NewGenerator(Options{TemplateDir: dir}).loadTemplateSet(); err == nil {
+				t.Errorf("loadTemplateSet() expected error")
+			}
+		})
+	}
+
+	specs, err := NewGenerator(Options{TemplateDir: t.TempDir()}).loadTemplateSet()
+	if err != nil || specs != nil {
+		t.Errorf("loadTemplateSet() = %v, %v, want no templates without manifest", specs, err)
+	}
+}
+
+func Test_outputName(t *testing.T) {
+	for _, output := range []string{"../{{.Key}}.go", "/tmp/{{.Key}}.go", " "} {
+		spec := &templateSpec{Template: "a.tpl", output: template.Must(template.New("").Parse(output))}
+		if name, err := spec.outputName(&Action{Key: "search"}); err == nil {
+			t.Errorf("outputName() = %v, expected error for %q", name, output)
+		}
+	}
+}
@@ -0,0 +1,338 @@
+{{- /* clientCore is the request plumbing of generated clients: the single package client includes it,
+versioned packages share it through the internal core package. It's built on the fields declared by clientFields. */}}
+{{- define "clientFields"}}
+	host string
+	username string
+	password string
+	transport *http.Client
+
+	versionMu sync.Mutex
+	version *serverVersion
+{{- end}}
+
+{{- define "clientCore"}}
+type httpErrorResponse struct {
+	Errors []*httpErrorResponseMsg {{tick}}json:"errors"{{tick}}
+}
+
+func (er *httpErrorResponse) String() string {
+	msgA := make([]string, len(er.Errors))
+	for i, em := range er.Errors {
+		msgA[i] = em.String()
+	}
+	return strings.Join(msgA, ", ")
+}
+
+type httpErrorResponseMsg struct {
+	Msg string {{tick}}json:"msg"{{tick}}
+}
+
+func (em *httpErrorResponseMsg) String() string {
+	return em.Msg
+}
+
+type HttpError struct {
+	status   int
+	parsed   *httpErrorResponse
+	response *http.Response
+	err      error
+}
+
+func (he *HttpError) Error() string {
+	return fmt.Sprintf("http code - %d, msg: %s", he.status, he.parsed.String())
+}
+
+func (he *HttpError) Response() *http.Response {
+	return he.response
+}
+
+func checkHttpErrors(resp *http.Response) error {
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusAccepted:
+		return nil
+	default:
+		var err error
+		bytesData, err := ioutil.ReadAll(resp.Body)
+
+		errorResponse := &httpErrorResponse{}
+		if err == nil {
+			err = json.Unmarshal(bytesData, errorResponse)
+		}
+
+		if err != nil {
+			msg := &httpErrorResponseMsg{
+				Msg: "Unknown error: " + err.Error(),
+			}
+			errorResponse = &httpErrorResponse{
+				Errors: []*httpErrorResponseMsg{msg},
+			}
+		}
+
+		result := &HttpError{
+			status:   resp.StatusCode,
+			response: resp,
+			parsed:   errorResponse,
+		}
+		return result
+	}
+}
+
+func (c *Client) invoke(ctx context.Context, post bool, url string, values url.Values) (*http.Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	url = c.host + "/" + url
+
+	method := http.MethodGet
+	if post {
+		method = http.MethodPost
+	}
+
+	var err error
+	var req *http.Request
+	var body io.Reader
+	if method == http.MethodGet {
+		url = url + "?" + values.Encode()
+	} else {
+		body = strings.NewReader(values.Encode())
+	}
+
+	req, err = http.NewRequestWithContext(ctx, method, url, body)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	req.Header.Set("content-type", "application/x-www-form-urlencoded")
+
+	// a token is sent as the username with an empty password
+	if len(c.username) != 0 {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.transport.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkHttpErrors(resp); err != nil {
+		return nil, errors.Wrapf(err, "got error response (url: %s)", url)
+	}
+	return resp, nil
+}
+
+// File is the content of a file param, it's sent as a part of multipart/form-data request
+type File struct {
+	// Name is the file name sent to the server
+	Name    string
+	Content io.Reader
+}
+
+// invokeMultipart sends values and files as multipart/form-data POST request, contents of files are streamed to the server
+func (c *Client) invokeMultipart(ctx context.Context, url string, values url.Values, files map[string]*File) (*http.Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	url = c.host + "/" + url
+
+	body, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+	go func() {
+		writer.CloseWithError(writeMultipart(form, values, files))
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		body.Close()
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	req.Header.Set("content-type", form.FormDataContentType())
+
+	// a token is sent as the username with an empty password
+	if len(c.username) != 0 {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.transport.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkHttpErrors(resp); err != nil {
+		return nil, errors.Wrapf(err, "got error response (url: %s)", url)
+	}
+	return resp, nil
+}
+
+func writeMultipart(form *multipart.Writer, values url.Values, files map[string]*File) error {
+	for key, list := range values {
+		for _, value := range list {
+			if err := form.WriteField(key, value); err != nil {
+				return err
+			}
+		}
+	}
+	for key, file := range files {
+		if file == nil {
+			continue
+		}
+		part, err := form.CreateFormFile(key, file.Name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(part, file.Content); err != nil {
+			return errors.Wrapf(err, "failed to upload %s", key)
+		}
+	}
+	return form.Close()
+}
+
+// Download is the binary or text content of a response streamed from the server,
+// it must be closed, WriteTo closes it after copying the content
+type Download struct {
+	// ContentType is the content type of the response, e.g. image/svg+xml
+	ContentType string
+	// ContentLength is the length of the content, -1 if it's unknown
+	ContentLength int64
+
+	body io.ReadCloser
+}
+
+func newDownload(resp *http.Response) *Download {
+	return &Download{
+		ContentType:   resp.Header.Get("content-type"),
+		ContentLength: resp.ContentLength,
+		body:          resp.Body,
+	}
+}
+
+func (d *Download) Read(p []byte) (int, error) {
+	return d.body.Read(p)
+}
+
+func (d *Download) Close() error {
+	return d.body.Close()
+}
+
+// WriteTo copies the content to w and closes the download
+func (d *Download) WriteTo(w io.Writer) (int64, error) {
+	defer d.body.Close()
+	return io.Copy(w, d.body)
+}
+
+// SetServerVersion sets the version of the server, e.g. "7.1".
+// The version is used to send deprecated param keys to servers which don't support new ones,
+// if it's not set, it's fetched from the server when needed.
+func (c *Client) SetServerVersion(version string) error {
+	v, err := parseServerVersion(version)
+	if err != nil {
+		return err
+	}
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
+	c.version = v
+	return nil
+}
+
+func (c *Client) serverVersion(ctx context.Context) (*serverVersion, error) {
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
+	if c.version != nil {
+		return c.version, nil
+	}
+
+	resp, err := c.invoke(ctx, false, "api/server/version", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch server version")
+	}
+	defer resp.Body.Close()
+	raw, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch server version")
+	}
+	v, err := parseServerVersion(string(raw))
+	if err != nil {
+		return nil, err
+	}
+	c.version = v
+	return v, nil
+}
+
+// useDeprecatedKeys renames params to their deprecated keys if the server is older than the new keys
+func (c *Client) useDeprecatedKeys(ctx context.Context, values url.Values, keys []deprecatedKey) error {
+	var version *serverVersion
+	for _, k := range keys {
+		value, ok := values[k.Key]
+		if !ok {
+			continue
+		}
+		if version == nil {
+			var err error
+			if version, err = c.serverVersion(ctx); err != nil {
+				return err
+			}
+		}
+		if version.less(k.Since) {
+			delete(values, k.Key)
+			values[k.DeprecatedKey] = value
+		}
+	}
+	return nil
+}
+
+type deprecatedKey struct {
+	Key           string
+	DeprecatedKey string
+	Since         serverVersion
+}
+
+type serverVersion struct {
+	Major int
+	Minor int
+}
+
+func parseServerVersion(s string) (*serverVersion, error) {
+	seg := strings.Split(strings.TrimSpace(s), ".")
+	major, err := strconv.Atoi(seg[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse server version %q", s)
+	}
+	v := &serverVersion{Major: major}
+	if len(seg) >= 2 {
+		if v.Minor, err = strconv.Atoi(seg[1]); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse server version %q", s)
+		}
+	}
+	return v, nil
+}
+
+func (v *serverVersion) less(o serverVersion) bool {
+	return v.Major < o.Major || v.Major == o.Major && v.Minor < o.Minor
+}
+
+// String Helper function to convert string to pointer to string
+func String(v string) *string {
+	p := new(string)
+	*p = v
+	return p
+}
+
+// Bool Helper function to convert bool to pointer to bool
+func Bool(v bool) *bool {
+	p := new(bool)
+	*p = v
+	return p
+}
+
+func encodeList(v []string) string {
+	return strings.Join(v, ",")
+}
+
+func encodeBool(v bool, t, f string) string {
+	if v {
+		return t
+	}
+	return f
+}
+{{- end}}
@@ -0,0 +1,149 @@
+package {{.PackageName}}
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TaskStatus is the status of a Compute Engine task
+type TaskStatus string
+
+const (
+	TaskPending    TaskStatus = "PENDING"
+	TaskInProgress TaskStatus = "IN_PROGRESS"
+	TaskSuccess    TaskStatus = "SUCCESS"
+	TaskFailed     TaskStatus = "FAILED"
+	TaskCanceled   TaskStatus = "CANCELED"
+)
+
+// Done is true for final statuses
+func (s TaskStatus) Done() bool {
+	return s == TaskSuccess || s == TaskFailed || s == TaskCanceled
+}
+
+// Task is a Compute Engine task, e.g. processing of an analysis report or a project dump
+type Task struct {
+	ID              string     {{tick}}json:"id"{{tick}}
+	Type            string     {{tick}}json:"type"{{tick}}
+	ComponentKey    string     {{tick}}json:"componentKey"{{tick}}
+	Branch          string     {{tick}}json:"branch"{{tick}}
+	// BranchType is BRANCH or PULL_REQUEST on editions supporting branches
+	BranchType      string     {{tick}}json:"branchType"{{tick}}
+	PullRequest     string     {{tick}}json:"pullRequest"{{tick}}
+	Status          TaskStatus {{tick}}json:"status"{{tick}}
+	AnalysisID      string     {{tick}}json:"analysisId"{{tick}}
+	ErrorMessage    string     {{tick}}json:"errorMessage"{{tick}}
+	SubmittedAt     string     {{tick}}json:"submittedAt"{{tick}}
+	StartedAt       string     {{tick}}json:"startedAt"{{tick}}
+	ExecutedAt      string     {{tick}}json:"executedAt"{{tick}}
+	ExecutionTimeMs int64      {{tick}}json:"executionTimeMs"{{tick}}
+}
+
+// TaskError is returned by WaitForTask when the task failed or was canceled
+type TaskError struct {
+	Task *Task
+}
+
+func (e *TaskError) Error() string {
+	if e.Task.ErrorMessage != "" {
+		return fmt.Sprintf("task %s is %s: %s", e.Task.ID, e.Task.Status, e.Task.ErrorMessage)
+	}
+	return fmt.Sprintf("task %s is %s", e.Task.ID, e.Task.Status)
+}
+
+// WaitOptions configures polling of WaitForTask, zero values are replaced with defaults
+type WaitOptions struct {
+	// Interval is the delay before the second poll, default 1s
+	Interval time.Duration
+	// Backoff multiplies the delay after every poll, default 1.5, 1 polls with the constant interval
+	Backoff float64
+	// MaxInterval limits the delay growth, default 30s
+	MaxInterval time.Duration
+	// Timeout limits the whole waiting, 0 waits until the context is done
+	Timeout time.Duration
+}
+
+func (o *WaitOptions) withDefaults() WaitOptions {
+	result := WaitOptions{}
+	if o != nil {
+		result = *o
+	}
+	if result.Interval <= 0 {
+		result.Interval = time.Second
+	}
+	if result.Backoff < 1 {
+		result.Backoff = 1.5
+	}
+	if result.MaxInterval <= 0 {
+		result.MaxInterval = 30 * time.Second
+	}
+	if result.MaxInterval < result.Interval {
+		result.MaxInterval = result.Interval
+	}
+	return result
+}
+
+// WaitForTask polls the task until it's done, the task is returned with *TaskError if it failed or was canceled.
+// If the context is done or the timeout expires, the last polled task is returned with the error.
+func (s *{{.Service.ServiceName}}) WaitForTask(ctx context.Context, taskID string, opts *WaitOptions) (*Task, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	o := opts.withDefaults()
+	if o.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+
+	interval := o.Interval
+	for {
+		task, err := s.getTask(ctx, taskID)
+		if err != nil {
+			return nil, err
+		}
+		if task.Status.Done() {
+			if task.Status != TaskSuccess {
+				return task, &TaskError{Task: task}
+			}
+			return task, nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return task, errors.Wrapf(ctx.Err(), "task %s is %s", taskID, task.Status)
+		case <-timer.C:
+		}
+		interval = time.Duration(float64(interval) * o.Backoff)
+		if interval > o.MaxInterval {
+			interval = o.MaxInterval
+		}
+	}
+}
+
+func (s *{{.Service.ServiceName}}) getTask(ctx context.Context, taskID string) (*Task, error) {
+	resp, err := s.{{.Task.MethodName}}(ctx, &{{.Task.RequestTypeName}}{
+		{{.ID.ParamName}}: String(taskID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	result := struct {
+		Task *Task {{tick}}json:"task"{{tick}}
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode task %s", taskID)
+	}
+	if result.Task == nil {
+		return nil, errors.Errorf("task %s is missing in the response", taskID)
+	}
+	return result.Task, nil
+}
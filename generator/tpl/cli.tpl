@@ -0,0 +1,358 @@
+// Command sonarctl is a command line client for SonarQube {{.Version}} web-api.
+//
+// Usage:
+//
+//	sonarctl [-host url] [-output json|table|raw] <service> <action> [params]
+//
+// Credentials are read from SONAR_TOKEN or SONAR_USER and SONAR_PASSWORD environment variables,
+// the server from SONAR_HOST_URL.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	sq "{{.ImportPath}}"
+)
+
+type command struct {
+	description string
+	run         func(ctx context.Context, c *sq.Client, args []string) (io.ReadCloser, error)
+}
+
+type service struct {
+	description string
+	actions     map[string]*command
+}
+
+var services = map[string]*service{
+{{- range .WebServices}}
+	"{{.Name}}": {
+		description: {{.Description | plainText | quote}},
+		actions: map[string]*command{
+	{{- range .Actions}}
+			"{{.Key}}": {description: {{.Description | plainText | quote}}, run: run{{.ServiceName}}{{.MethodName}}},
+	{{- end}}
+		},
+	},
+{{- end}}
+}
+
+func main() {
+	host := flag.String("host", envOr("SONAR_HOST_URL", "{{.Host}}"), "SonarQube server")
+	output := flag.String("output", "json", "output format: json, table or raw")
+	flag.Usage = usage
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+	svc, ok := services[args[0]]
+	if !ok {
+		fail(fmt.Errorf("unknown service %q", args[0]))
+	}
+	if len(args) == 1 {
+		serviceUsage(args[0], svc)
+		os.Exit(2)
+	}
+	cmd, ok := svc.actions[args[1]]
+	if !ok {
+		fail(fmt.Errorf("unknown action %q of service %q", args[1], args[0]))
+	}
+
+	username, password := os.Getenv("SONAR_USER"), os.Getenv("SONAR_PASSWORD")
+	if token := os.Getenv("SONAR_TOKEN"); token != "" {
+		username, password = token, ""
+	}
+	c := sq.NewClient(nil, *host, username, password)
+
+	body, err := cmd.run(context.Background(), c, args[2:])
+	if err != nil {
+		fail(err)
+	}
+	defer body.Close()
+
+	if err := writeOutput(os.Stdout, body, *output); err != nil {
+		fail(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <service> <action> [params]\n\nFlags:\n", os.Args[0])
+	flag.PrintDefaults()
+	fmt.Fprint(flag.CommandLine.Output(), "\nServices:\n")
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	w := tabwriter.NewWriter(flag.CommandLine.Output(), 0, 4, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s\t%s\n", name, services[name].description)
+	}
+	w.Flush()
+}
+
+func serviceUsage(name string, svc *service) {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] %s <action> [params]\n\n%s\n\nActions:\n", os.Args[0], name, svc.description)
+	actions := make([]string, 0, len(svc.actions))
+	for action := range svc.actions {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	w := tabwriter.NewWriter(flag.CommandLine.Output(), 0, 4, 2, ' ', 0)
+	for _, action := range actions {
+		fmt.Fprintf(w, "  %s\t%s\n", action, svc.actions[action].description)
+	}
+	w.Flush()
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+
+func envOr(key, value string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return value
+}
+
+func newFlagSet(name, description string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s [params]\n\n%s\n\nParams:\n", os.Args[0], name, description)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses args and returns values of the flags set explicitly
+func parseFlags(fs *flag.FlagSet, args []string, required []string) (map[string]string, error) {
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	set := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = f.Value.String()
+	})
+	missing := make([]string, 0)
+	for _, name := range required {
+		if _, ok := set[name]; !ok {
+			missing = append(missing, "-"+name)
+		}
+	}
+	if len(missing) != 0 {
+		return nil, fmt.Errorf("%s: missing required params %s", fs.Name(), strings.Join(missing, ", "))
+	}
+	return set, nil
+}
+
+func stringFlag(set map[string]string, name string) *string {
+	v, ok := set[name]
+	if !ok {
+		return nil
+	}
+	return sq.String(v)
+}
+
+func boolFlag(set map[string]string, name string) (*bool, error) {
+	v, ok := set[name]
+	if !ok {
+		return nil, nil
+	}
+	switch v {
+	case "yes":
+		return sq.Bool(true), nil
+	case "no":
+		return sq.Bool(false), nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q of -%s：%w", v, name, err)
+	}
+	return sq.Bool(b), nil
+}
+
+func listFlag(set map[string]string, name string) []string {
+	v, ok := set[name]
+	if !ok {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
+
+// fileFlag opens the file of the flag, it's closed when the tool exits
+func fileFlag(set map[string]string, name string) (*sq.File, error) {
+	v, ok := set[name]
+	if !ok {
+		return nil, nil
+	}
+	file, err := os.Open(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value of -%s：%w", name, err)
+	}
+	return &sq.File{Name: filepath.Base(v), Content: file}, nil
+}
+
+func writeOutput(w io.Writer, r io.Reader, output string) error {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read response：%w", err)
+	}
+	var doc interface{}
+	if output == "raw" || json.Unmarshal(body, &doc) != nil {
+		_, err = w.Write(body)
+		return err
+	}
+	if output == "table" {
+		return writeTable(w, doc)
+	}
+	buff := new(bytes.Buffer)
+	if err := json.Indent(buff, body, "", "  "); err != nil {
+		return err
+	}
+	buff.WriteString("\n")
+	_, err = buff.WriteTo(w)
+	return err
+}
+
+// writeTable prints the first list of objects found in the document as a table,
+// or the document (or its only object) itself if there is no such list
+func writeTable(w io.Writer, doc interface{}) error {
+	rows := tableRows(doc)
+	columns := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		for column := range row {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		}
+	}
+	sort.Strings(columns)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(columns, "\t")))
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			cells[i] = tableCell(row[column])
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+func tableRows(doc interface{}) []map[string]interface{} {
+	switch v := doc.(type) {
+	case []interface{}:
+		rows := make([]map[string]interface{}, 0, len(v))
+		for _, item := range v {
+			if row, ok := item.(map[string]interface{}); ok {
+				rows = append(rows, row)
+			}
+		}
+		return rows
+	case map[string]interface{}:
+		if len(v) == 1 {
+			for _, item := range v {
+				if _, ok := item.(map[string]interface{}); ok {
+					return tableRows(item)
+				}
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if list, ok := v[key].([]interface{}); ok && len(list) != 0 {
+				if _, ok := list[0].(map[string]interface{}); ok {
+					return tableRows(list)
+				}
+			}
+		}
+		return []map[string]interface{}{v}
+	default:
+		return []map[string]interface{}{ {"value": v} }
+	}
+}
+
+func tableCell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		raw, _ := json.Marshal(v)
+		return string(raw)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+{{- range $ws := .WebServices}}
+{{- range .Actions}}
+
+func run{{.ServiceName}}{{.MethodName}}(ctx context.Context, c *sq.Client, args []string) (io.ReadCloser, error) {
+	fs := newFlagSet("{{$ws.Name}} {{.Key}}", {{.Description | plainText | quote}})
+	{{- range .Params}}
+	fs.String("{{.Key}}", "", {{flagUsage . | quote}})
+	{{- end}}
+	{{- if .Params}}
+	set, err := parseFlags(fs, args, []string{ {{- range .Params}}{{if .Required}}"{{.Key}}", {{end}}{{end -}} })
+	{{- else}}
+	_, err := parseFlags(fs, args, nil)
+	{{- end}}
+	if err != nil {
+		return nil, err
+	}
+	{{- if .Params}}
+	request := &sq.{{.RequestTypeName}}{}
+	{{- range .Params}}
+	{{- if .IsFile}}
+	if request.{{.ParamName}}, err = fileFlag(set, "{{.Key}}"); err != nil {
+		return nil, err
+	}
+	{{- else if .IsList}}
+	request.{{.ParamName}} = listFlag(set, "{{.Key}}")
+	{{- else if .IsBool}}
+	if request.{{.ParamName}}, err = boolFlag(set, "{{.Key}}"); err != nil {
+		return nil, err
+	}
+	{{- else}}
+	request.{{.ParamName}} = stringFlag(set, "{{.Key}}")
+	{{- end}}
+	{{- end}}
+	resp, err := c.{{$ws.Getter}}().{{.MethodName}}(ctx, request)
+	{{- else}}
+	resp, err := c.{{$ws.Getter}}().{{.MethodName}}(ctx)
+	{{- end}}
+	if err != nil {
+		return nil, err
+	}
+	{{- if .IsStream}}
+	return resp.Download, nil
+	{{- else}}
+	return resp.Body, nil
+	{{- end}}
+}
+{{- end}}
+{{- end}}
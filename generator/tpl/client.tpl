@@ -0,0 +1,71 @@
+package {{.PackageName}}
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+type Client struct {
+	{{- template "clientFields"}}
+{{- range .WebServices}}
+	{{.Variable}} *{{.ServiceName}}
+{{- end }}
+{{- with .ClientExtType}}
+	// {{.}} is declared in a hand-written *_ext.go file
+	{{.}}
+{{- end}}
+}
+
+func NewClient(httpClient *http.Client, host, username, password string) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	c := &Client{
+		host: host,
+		transport: httpClient,
+		username: username,
+		password: password,
+	}
+
+{{- range .WebServices}}
+	c.{{.Variable}} = New{{.ServiceName}}(c)
+{{- end }}
+{{- if .HasClientExtInit}}
+	c.initExt()
+{{- end}}
+
+	return c
+}
+
+{{- range .WebServices}}
+{{- template "getter" .}}
+{{- end}}
+
+{{- define "getter"}}
+// {{.Getter}} {{.Description | formatDescription}}
+{{- if .Since }}
+// Since : {{.Since}}
+{{- end}}
+{{- if .Deprecated }}
+// Deprecated
+{{- end}}
+{{- if .Internal }}
+// Internal
+{{- end}}
+func (c *Client) {{.Getter}}() *{{.ServiceName}} {
+	return c.{{.Variable}}
+}
+{{- end}}
+
+{{template "clientCore" .}}
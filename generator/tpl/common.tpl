@@ -0,0 +1,44 @@
+// Package {{.PackageName}} is the part of SonarQube web-api clients shared by the packages of different server versions:
+{{- range .Packages}}
+//   - {{.}}
+{{- end}}
+package {{.PackageName}}
+
+import (
+	"net/http"
+
+	core "{{.CoreImport}}"
+)
+
+// Client sends requests to the server, the clients of versioned packages wrap it
+type Client = core.Client
+
+type HttpError = core.HttpError
+
+// File is the content of a file param, it's sent as a part of multipart/form-data request
+type File = core.File
+
+// Download is the binary or text content of a response streamed from the server
+type Download = core.Download
+
+// Version is the major and minor version of the server
+type Version = core.Version
+
+func NewClient(httpClient *http.Client, host, username, password string) *Client {
+	return core.NewClient(httpClient, host, username, password)
+}
+
+// ParseVersion parses the version of the server, e.g. "9.9"
+func ParseVersion(s string) (*Version, error) {
+	return core.ParseVersion(s)
+}
+
+// String Helper function to convert string to pointer to string
+func String(v string) *string {
+	return core.String(v)
+}
+
+// Bool Helper function to convert bool to pointer to bool
+func Bool(v bool) *bool {
+	return core.Bool(v)
+}
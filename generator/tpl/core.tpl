@@ -0,0 +1,77 @@
+// Package {{.PackageName}} is the request plumbing shared by the common package and the packages of server versions,
+// it's internal, so the plumbing isn't a part of their api.
+package {{.PackageName}}
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Client keeps the connection, credentials and the version of the server shared by the clients of versioned packages
+type Client struct {
+	{{- template "clientFields"}}
+}
+
+func NewClient(httpClient *http.Client, host, username, password string) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		host: host,
+		transport: httpClient,
+		username: username,
+		password: password,
+	}
+}
+
+// ServerVersion returns the version set by SetServerVersion or fetches it from the server
+func (c *Client) ServerVersion(ctx context.Context) (*Version, error) {
+	return c.serverVersion(ctx)
+}
+
+// Version is the major and minor version of the server
+type Version = serverVersion
+
+// DeprecatedKey is the key of a param renamed to Key in the Since version
+type DeprecatedKey = deprecatedKey
+
+func ParseVersion(s string) (*Version, error) {
+	return parseServerVersion(s)
+}
+
+func Invoke(c *Client, ctx context.Context, post bool, url string, values url.Values) (*http.Response, error) {
+	return c.invoke(ctx, post, url, values)
+}
+
+func InvokeMultipart(c *Client, ctx context.Context, url string, values url.Values, files map[string]*File) (*http.Response, error) {
+	return c.invokeMultipart(ctx, url, values, files)
+}
+
+func UseDeprecatedKeys(c *Client, ctx context.Context, values url.Values, keys []DeprecatedKey) error {
+	return c.useDeprecatedKeys(ctx, values, keys)
+}
+
+func NewDownload(resp *http.Response) *Download {
+	return newDownload(resp)
+}
+
+func EncodeList(v []string) string {
+	return encodeList(v)
+}
+
+func EncodeBool(v bool, t, f string) string {
+	return encodeBool(v, t, f)
+}
+
+{{template "clientCore" .}}
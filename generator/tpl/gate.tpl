@@ -0,0 +1,240 @@
+package {{.PackageName}}
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Quality gate statuses, NONE is the status of projects without quality gate
+const (
+	GateOK    = "OK"
+	GateWarn  = "WARN"
+	GateError = "ERROR"
+	GateNone  = "NONE"
+)
+
+// GateOptions selects the analysis which quality gate status is resolved
+type GateOptions struct {
+	ProjectKey string
+{{- if .Branch}}
+	Branch string
+{{- end}}
+{{- if .PullRequest}}
+	PullRequest string
+{{- end}}
+{{- if .CE}}
+	// TaskID is the Compute Engine task of the analysis, e.g. ceTaskId of report-task.txt written by the scanner,
+	// the status of its analysis is returned after the task is done
+	TaskID string
+	// Wait configures waiting for the task
+	Wait *WaitOptions
+{{- end}}
+}
+
+// GateStatus is the quality gate status of an analysis
+type GateStatus struct {
+	// Status is one of GateOK, GateWarn, GateError or GateNone
+	Status string {{tick}}json:"status"{{tick}}
+	// Passed is false only if the quality gate failed
+	Passed     bool             {{tick}}json:"-"{{tick}}
+	Conditions []*GateCondition {{tick}}json:"conditions"{{tick}}
+}
+
+// GateCondition is a condition of the quality gate and the actual value of its metric
+type GateCondition struct {
+	Status           string {{tick}}json:"status"{{tick}}
+	MetricKey        string {{tick}}json:"metricKey"{{tick}}
+	// Comparator is LT, GT, EQ or NE
+	Comparator       string {{tick}}json:"comparator"{{tick}}
+	PeriodIndex      int    {{tick}}json:"periodIndex"{{tick}}
+	ErrorThreshold   string {{tick}}json:"errorThreshold"{{tick}}
+	WarningThreshold string {{tick}}json:"warningThreshold"{{tick}}
+	ActualValue      string {{tick}}json:"actualValue"{{tick}}
+	// Passed is false only if the condition failed
+	Passed bool {{tick}}json:"-"{{tick}}
+}
+
+var gateComparators = map[string]string{"LT": "<", "GT": ">", "EQ": "=", "NE": "!="}
+
+// Summary returns the human-readable verdict and conditions, e.g. for CI logs
+func (g *GateStatus) Summary() string {
+	summary := new(strings.Builder)
+	verdict := "passed"
+	if !g.Passed {
+		verdict = "failed"
+	}
+	fmt.Fprintf(summary, "Quality gate %s (%s)", verdict, g.Status)
+	for _, c := range g.Conditions {
+		comparator, ok := gateComparators[c.Comparator]
+		if !ok {
+			comparator = c.Comparator
+		}
+		threshold := c.ErrorThreshold
+		if threshold == "" {
+			threshold = c.WarningThreshold
+		}
+		fmt.Fprintf(summary, "\n  [%s] %s = %s, fails if %s %s", c.Status, c.MetricKey, c.ActualValue, comparator, threshold)
+	}
+	return summary.String()
+}
+
+// ProjectGate resolves the quality gate status of the project analysis.
+{{- if .CE}}
+// If TaskID is set{{if .Activity}} or the project has a pending Compute Engine task{{end}}, the task is waited for
+// and the status of its analysis is returned, a failed task is returned as *TaskError.
+{{- end}}
+func (s *{{.Service.ServiceName}}) ProjectGate(ctx context.Context, opts *GateOptions) (*GateStatus, error) {
+	if opts == nil || opts.ProjectKey == "" {
+		return nil, errors.New("project key is required")
+	}
+	request := &{{.Status.RequestTypeName}}{
+		{{.ProjectKey.ParamName}}: String(opts.ProjectKey),
+	}
+	{{- if .Branch}}
+	if opts.Branch != "" {
+		request.{{.Branch.ParamName}} = String(opts.Branch)
+	}
+	{{- end}}
+	{{- if .PullRequest}}
+	if opts.PullRequest != "" {
+		request.{{.PullRequest.ParamName}} = String(opts.PullRequest)
+	}
+	{{- end}}
+{{- if .CE}}
+
+	taskID := opts.TaskID
+	{{- if .Activity}}
+	if taskID == "" {
+		task, err := s.pendingTask(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		if task != nil {
+			taskID = task.ID
+		}
+	}
+	{{- end}}
+	if taskID != "" {
+		task, err := s.client.{{.CE.Service.Variable}}.WaitForTask(ctx, taskID, opts.Wait)
+		if err != nil {
+			return nil, err
+		}
+		{{- if .AnalysisID}}
+		if task.AnalysisID != "" {
+			request = &{{.Status.RequestTypeName}}{
+				{{.AnalysisID.ParamName}}: String(task.AnalysisID),
+			}
+		}
+		{{- end}}
+	}
+{{- end}}
+
+	resp, err := s.{{.Status.MethodName}}(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	result := struct {
+		ProjectStatus *GateStatus {{tick}}json:"projectStatus"{{tick}}
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode quality gate status of %s", opts.ProjectKey)
+	}
+	if result.ProjectStatus == nil {
+		return nil, errors.Errorf("quality gate status of %s is missing in the response", opts.ProjectKey)
+	}
+	status := result.ProjectStatus
+	status.Passed = status.Status != GateError
+	for _, c := range status.Conditions {
+		c.Passed = c.Status != GateError
+	}
+	return status, nil
+}
+{{- if .Activity}}
+
+// pendingTask returns the latest pending or in progress task of the project, or nil if there is none.
+// Tasks are matched by the branch and the pull request, empty values match only tasks of the main branch.
+func (s *{{.Service.ServiceName}}) pendingTask(ctx context.Context, opts *GateOptions) (*Task, error) {
+	resp, err := s.client.{{.CE.Service.Variable}}.{{.Activity.MethodName}}(ctx, &{{.Activity.RequestTypeName}}{
+		{{.ActivityComponent.ParamName}}: String(opts.ProjectKey),
+		{{.ActivityStatus.ParamName}}:    []string{string(TaskPending), string(TaskInProgress)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	result := struct {
+		Tasks []*Task {{tick}}json:"tasks"{{tick}}
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode pending tasks of %s", opts.ProjectKey)
+	}
+	{{- if .Branches}}
+	mainBranch := ""
+	{{- end}}
+	for _, task := range result.Tasks {
+		{{- if .PullRequest}}
+		if task.PullRequest != opts.PullRequest {
+			continue
+		}
+		{{- end}}
+		{{- if .Branch}}
+		if task.Branch != opts.Branch {
+			{{- if .Branches}}
+			// the task of the main branch has the branch name, it matches the empty branch
+			if opts.Branch != "" || task.PullRequest != "" || task.BranchType == "PULL_REQUEST" {
+				continue
+			}
+			if mainBranch == "" {
+				if mainBranch, err = s.mainBranch(ctx, opts.ProjectKey); err != nil {
+					return nil, err
+				}
+			}
+			if task.Branch != mainBranch {
+				continue
+			}
+			{{- else}}
+			continue
+			{{- end}}
+		}
+		{{- end}}
+		return task, nil
+	}
+	return nil, nil
+}
+{{- end}}
+{{- if .Branches}}
+
+// mainBranch returns the name of the main branch of the project
+func (s *{{.Service.ServiceName}}) mainBranch(ctx context.Context, projectKey string) (string, error) {
+	resp, err := s.client.{{.BranchesService.Variable}}.{{.Branches.MethodName}}(ctx, &{{.Branches.RequestTypeName}}{
+		{{.BranchesProject.ParamName}}: String(projectKey),
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	result := struct {
+		Branches []struct {
+			Name   string {{tick}}json:"name"{{tick}}
+			IsMain bool   {{tick}}json:"isMain"{{tick}}
+		} {{tick}}json:"branches"{{tick}}
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", errors.Wrapf(err, "failed to decode branches of %s", projectKey)
+	}
+	for _, branch := range result.Branches {
+		if branch.IsMain {
+			return branch.Name, nil
+		}
+	}
+	return "", errors.Errorf("main branch of %s is missing in the response", projectKey)
+}
+{{- end}}
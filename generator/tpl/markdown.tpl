@@ -0,0 +1,59 @@
+{{- define "index" -}}
+# SonarQube Web API {{.Version}}
+
+Go package `{{.PackageName}}`, client is created with `{{.PackageName}}.NewClient(httpClient, host, username, password)`.
+
+| Service | Getter | Description |
+|---------|--------|-------------|
+{{- range .WebServices}}
+| [{{.Path}}]({{.Name}}.md) | `client.{{.Getter}}()` | {{.Description | markdownCell}}{{if .Deprecated}} `deprecated`{{end}}{{if .Internal}} `internal`{{end}} |
+{{- end}}
+{{end -}}
+
+{{- define "service" -}}
+{{- $ws := . -}}
+# {{.Path}}
+{{template "badges" .}}
+
+{{.Description | markdown}}
+
+Go: `client.{{.Getter}}()` returns `*{{.ServiceName}}`.
+
+## Actions
+{{range .Actions}}
+- [{{.Key}}](#{{.Key}})
+{{- end}}
+{{range .Actions}}
+## {{.Key}}
+
+`{{if .Post}}POST{{else}}GET{{end}} {{$ws.Path}}/{{.Key}}`
+{{template "badges" .}}
+
+{{.Description | markdown}}
+
+```go
+func (s *{{.ServiceName}}) {{.MethodName}}(ctx context.Context{{if .Params}}, request *{{.RequestTypeName}}{{end}}) (*{{.ResponseTypeName}}, error)
+```
+{{- if .Params}}
+
+| Param | Go field | Type | Required | Default | Possible values | Description |
+|-------|----------|------|----------|---------|-----------------|-------------|
+{{- range .Params}}
+| `{{.Key}}`{{template "badges" .}} | `{{.ParamName}}` | `{{.GoType}}` | {{if .Required}}yes{{else}}no{{end}} | {{if .DefaultValue}}`{{.DefaultValue}}`{{end}} | {{join .PossibleValues ", "}} | {{.Description | markdownCell}}{{if .DeprecatedKey}} Deprecated key `{{.DeprecatedKey}}` since {{.DeprecatedKeySince}}.{{end}} |
+{{- end}}
+{{- end}}
+{{- if .Changelog}}
+
+Changelog:
+{{range .Changelog}}
+- {{.Version}}: {{.Description | markdownCell}}
+{{- end}}
+{{- end}}
+{{end}}
+{{- end -}}
+
+{{- define "badges" -}}
+{{- if .Since.String}} `since {{.Since}}`{{end -}}
+{{- if .Deprecated}} `deprecated{{if .DeprecatedSince.String}} since {{.DeprecatedSince}}{{end}}`{{end -}}
+{{- if .Internal}} `internal`{{end -}}
+{{- end -}}
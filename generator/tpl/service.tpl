@@ -0,0 +1,219 @@
+package {{.PackageName}}
+
+import (
+	"context"
+{{- if .HasResponses}}
+	"net/http"
+{{- end}}
+{{- if .HasRequests}}
+	"net/url"
+{{- end}}
+
+	"github.com/pkg/errors"
+)
+
+// {{.ServiceName}} {{.Description | formatDescription }}
+{{- if .Since }}
+// Since : {{.Since}}
+{{- end}}
+{{- if .Deprecated }}
+// Deprecated
+{{- end}}
+{{- if .Internal }}
+// Internal
+{{- end}}
+type {{.ServiceName}} struct {
+	client *Client
+	url string
+{{- with .ExtType}}
+	// {{.}} is declared in a hand-written *_ext.go file
+	{{.}}
+{{- end}}
+}
+
+
+{{- if .Deprecated }}
+// Deprecated
+{{- end}}
+func New{{.ServiceName}} (client *Client) *{{.ServiceName}}{
+	s := &{{.ServiceName}}{
+		client: client,
+		url: "{{.Path}}",
+	}
+{{- if .HasExtInit}}
+	s.initExt()
+{{- end}}
+	return s
+}
+
+{{- range $index, $element := .Actions}}
+{{ template "action" $element}}
+{{- end}}
+
+{{- define "action"}}
+// {{ .MethodName }} {{.Description | formatDescription }}
+{{- if .Since}}
+// Since {{.Since}}
+{{- end}}
+{{- if len .Changelog }}
+//
+// Changelog:
+	{{- range .Changelog }}
+//   - {{.Version}}: {{.Description | plainText}}
+	{{- end}}
+{{- end}}
+{{- if .Deprecated }}
+//
+// Deprecated since {{.DeprecatedSince}}
+{{- end}}
+func (s *{{.ServiceName}}) {{.MethodName}} (ctx context.Context{{- if .Params}}, request *{{.RequestTypeName}}{{- end}}) (*{{.ResponseTypeName}}, error) {
+{{- if or .HasDeprecatedKeys .HasFiles}}
+	values := request.EncodeValues()
+	{{- if .HasDeprecatedKeys}}
+	if err := s.client.useDeprecatedKeys(ctx, values, request.deprecatedKeys()); err != nil {
+		return nil, errors.Wrap(err, "failed to call {{.ServiceName}}.{{.MethodName}}")
+	}
+	{{- end}}
+	{{- if .HasFiles}}
+	files, err := request.files()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to call {{.ServiceName}}.{{.MethodName}}")
+	}
+	resp, err := s.client.invokeMultipart(ctx, s.url + "/" + "{{.Key}}", values, files)
+	{{- else}}
+	resp, err := s.client.invoke(ctx, {{.Post}}, s.url + "/" + "{{.Key}}", values)
+	{{- end}}
+{{- else}}
+	resp, err := s.client.invoke(ctx, {{.Post}}, s.url + "/" + "{{.Key}}", {{- if .Params}} request.EncodeValues() {{- else}} nil {{- end}})
+{{- end}}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to call {{.ServiceName}}.{{.MethodName}}")
+	}
+	return &{{.ResponseTypeName}}{
+	{{- if .IsStream}}
+		Download: newDownload(resp),
+	{{- else}}
+		Response: resp,
+	{{- end}}
+	}, nil
+}
+{{- if .Params }}
+{{ template "request" .}}
+{{- end}}
+
+{{ template "response" .}}
+
+{{- end}}
+
+{{- define "request"}}
+type {{.RequestTypeName}} struct {
+{{- range .Params}}
+	// {{.Description | formatDescription }}
+	{{- if .Since | formatSince }}
+	// Since {{ .Since | formatSince }}
+	{{- end}}
+	{{- if .Required}}
+	// Required
+	{{- end }}
+	{{- if .Internal}}
+	// Internal
+	{{- end }}
+	{{- if .DefaultValue }}
+	// Default: {{.DefaultValue}}
+	{{- end}}
+	{{- if .ExampleValue}}
+	// Example: {{.ExampleValue}}
+	{{- end }}
+	{{- if .PossibleValues }}
+	// Possible values: {{- range .PossibleValues}} "{{.}}", {{- end}}
+	{{- end}}
+	{{- if .Deprecated}}
+	// Deprecated since {{.DeprecatedSince.String}}
+	{{- end }}
+	{{- if .DeprecatedKey}}
+	// Deprecated key: {{.DeprecatedKey}} (since {{.DeprecatedKeySince.String}}), it's sent instead of {{.Key}} to servers older than {{.DeprecatedKeySince.String}}
+	{{- end }}
+	{{.ParamName}} {{.GoType}}
+{{- end}}
+}
+
+// EncodeValues encodes the request into the url.Values sent to the server.
+// Unset fields are omitted{{if .HasFiles}}, files are sent separately{{end}}.
+func (r *{{.RequestTypeName}}) EncodeValues() url.Values {
+	values := make(url.Values)
+	if r == nil {
+		return values
+	}
+{{- range .Params}}
+	{{- if .IsFile}}
+	{{- else if .IsList}}
+	if len(r.{{.ParamName}}) != 0 {
+		values.Set("{{.Key}}", encodeList(r.{{.ParamName}}))
+	}
+	{{- else if .IsBool}}
+	if r.{{.ParamName}} != nil {
+		values.Set("{{.Key}}", encodeBool(*r.{{.ParamName}}, "{{.TrueValue}}", "{{.FalseValue}}"))
+	}
+	{{- else}}
+	if r.{{.ParamName}} != nil {
+		values.Set("{{.Key}}", *r.{{.ParamName}})
+	}
+	{{- end}}
+{{- end}}
+	return values
+}
+{{- if .HasFiles}}
+
+func (r *{{.RequestTypeName}}) files() (map[string]*File, error) {
+	if r == nil {
+		r = &{{.RequestTypeName}}{}
+	}
+{{- range .RequiredParams}}
+	{{- if .IsFile}}
+	if r.{{.ParamName}} == nil {
+		return nil, errors.New("file {{.Key}} is required")
+	}
+	{{- end}}
+{{- end}}
+{{- range .Params}}
+	{{- if .IsFile}}
+	if r.{{.ParamName}} != nil && r.{{.ParamName}}.Content == nil {
+		return nil, errors.New("file {{.Key}} has no content")
+	}
+	{{- end}}
+{{- end}}
+	return map[string]*File{
+{{- range .Params}}
+	{{- if .IsFile}}
+		"{{.Key}}": r.{{.ParamName}},
+	{{- end}}
+{{- end}}
+	}, nil
+}
+{{- end}}
+{{- if .HasDeprecatedKeys}}
+
+func (r *{{.RequestTypeName}}) deprecatedKeys() []deprecatedKey {
+	return []deprecatedKey{
+{{- range .Params}}
+	{{- if .DeprecatedKey}}
+		{Key: "{{.Key}}", DeprecatedKey: "{{.DeprecatedKey}}", Since: serverVersion{Major: {{.DeprecatedKeySince.Major}}, Minor: {{.DeprecatedKeySince.Minor}}}},
+	{{- end}}
+{{- end}}
+	}
+}
+{{- end}}
+{{- end}}
+
+{{- define "response"}}
+{{- if .IsStream}}
+// {{.ResponseTypeName}} streams the content of the response, it must be closed
+type {{.ResponseTypeName}} struct {
+	*Download
+}
+{{- else}}
+type {{.ResponseTypeName}} struct {
+	*http.Response
+}
+{{- end}}
+{{- end}}
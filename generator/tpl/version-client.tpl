@@ -0,0 +1,109 @@
+// Package {{.PackageName}} is the client of SonarQube {{.Version}} web-api.
+package {{.PackageName}}
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	common "{{.CommonImport}}"
+	core "{{.CoreImport}}"
+)
+
+// Client is the client of SonarQube {{.Version}} web-api, connection, credentials and the server version
+// are kept by the embedded common client
+type Client struct {
+	*common.Client
+{{- range .WebServices}}
+	{{.Variable}} *{{.ServiceName}}
+{{- end }}
+{{- with .ClientExtType}}
+	// {{.}} is declared in a hand-written *_ext.go file
+	{{.}}
+{{- end}}
+}
+
+type HttpError = common.HttpError
+
+type File = common.File
+
+type Download = common.Download
+
+type deprecatedKey = core.DeprecatedKey
+
+type serverVersion = core.Version
+
+func NewClient(httpClient *http.Client, host, username, password string) *Client {
+	return Wrap(common.NewClient(httpClient, host, username, password))
+}
+
+// Wrap returns the client of SonarQube {{.Version}} web-api sending requests with the common client,
+// clients of different versions wrapping the same common client share its connection and credentials
+func Wrap(client *common.Client) *Client {
+	c := &Client{
+		Client: client,
+	}
+
+{{- range .WebServices}}
+	c.{{.Variable}} = New{{.ServiceName}}(c)
+{{- end }}
+{{- if .HasClientExtInit}}
+	c.initExt()
+{{- end}}
+
+	return c
+}
+
+func (c *Client) invoke(ctx context.Context, post bool, url string, values url.Values) (*http.Response, error) {
+	return core.Invoke(c.Client, ctx, post, url, values)
+}
+
+func (c *Client) invokeMultipart(ctx context.Context, url string, values url.Values, files map[string]*File) (*http.Response, error) {
+	return core.InvokeMultipart(c.Client, ctx, url, values, files)
+}
+
+func newDownload(resp *http.Response) *Download {
+	return core.NewDownload(resp)
+}
+
+func (c *Client) useDeprecatedKeys(ctx context.Context, values url.Values, keys []deprecatedKey) error {
+	return core.UseDeprecatedKeys(c.Client, ctx, values, keys)
+}
+
+{{- range .WebServices}}
+{{- template "getter" .}}
+{{- end}}
+
+{{- define "getter"}}
+// {{.Getter}} {{.Description | formatDescription}}
+{{- if .Since }}
+// Since : {{.Since}}
+{{- end}}
+{{- if .Deprecated }}
+// Deprecated
+{{- end}}
+{{- if .Internal }}
+// Internal
+{{- end}}
+func (c *Client) {{.Getter}}() *{{.ServiceName}} {
+	return c.{{.Variable}}
+}
+{{- end}}
+
+// String Helper function to convert string to pointer to string
+func String(v string) *string {
+	return common.String(v)
+}
+
+// Bool Helper function to convert bool to pointer to bool
+func Bool(v bool) *bool {
+	return common.Bool(v)
+}
+
+func encodeList(v []string) string {
+	return core.EncodeList(v)
+}
+
+func encodeBool(v bool, t, f string) string {
+	return core.EncodeBool(v, t, f)
+}
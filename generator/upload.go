@@ -0,0 +1,52 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// knownFileParams are params uploaded as files, the api definition doesn't tell files from other params
+var knownFileParams = []string{
+	"api/ce/submit?report",
+	"api/qualityprofiles/restore?backup",
+	"api/qualityprofiles/restore_built_in?backup",
+}
+
+// ParseFileParams parses the comma separated list of file params in the api/service/action?param form
+func ParseFileParams(str string) ([]string, error) {
+	params := make([]string, 0)
+	for _, item := range strings.Split(str, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if action, key, ok := strings.Cut(item, "?"); !ok || action == "" || key == "" {
+			return nil, fmt.Errorf("invalid file param %q, it must be in the api/service/action?param form", item)
+		}
+		params = append(params, item)
+	}
+	return params, nil
+}
+
+// markFileParams marks the known file params and the extra ones as files,
+// actions uploading files are always sent as POST requests
+func markFileParams(def *APIDefinition, extra []string) {
+	files := make(map[string]bool, len(knownFileParams)+len(extra))
+	for _, key := range knownFileParams {
+		files[key] = true
+	}
+	for _, key := range extra {
+		files[key] = true
+	}
+	for _, service := range def.WebServices {
+		for _, action := range service.Actions {
+			for _, p := range action.Params {
+				p.file = files[service.Path+"/"+action.Key+"?"+p.Key]
+			}
+			if action.HasFiles() && !action.Post {
+				action.Post = true
+				def.warnings = append(def.warnings, fmt.Sprintf("%s/%s uploads files, it's sent as POST request", service.Path, action.Key))
+			}
+		}
+	}
+}
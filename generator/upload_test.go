@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseFileParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		str     string
+		want    []string
+		wantErr bool
+	}{
+		{name: "should parse an empty list", str: "", want: []string{}},
+		{
+			name: "should parse params",
+			str:  "api/plugins/upload?file, api/settings/set?value",
+			want: []string{"api/plugins/upload?file", "api/settings/set?value"},
+		},
+		{name: "should fail on a param without action", str: "file", wantErr: true},
+		{name: "should fail on an action without param", str: "api/plugins/upload?", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFileParams(tt.str)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseFileParams() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFileParams() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_markFileParams(t *testing.T) {
+	restore := &Action{Key: "restore", Post: true, Params: []*Param{{Key: "backup"}, {Key: "organization"}}}
+	upload := &Action{Key: "upload", Params: []*Param{{Key: "file"}}}
+	def := &APIDefinition{WebServices: []*WebService{
+		{Path: "api/qualityprofiles", Actions: []*Action{restore}},
+		{Path: "api/plugins", Actions: []*Action{upload}},
+	}}
+	markFileParams(def, []string{"api/plugins/upload?file"})
+
+	got := []bool{restore.Params[0].IsFile(), restore.Params[1].IsFile(), upload.Params[0].IsFile(), upload.Post}
+	want := []bool{true, false, true, true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("markFileParams() = %v, want %v", got, want)
+	}
+	if restore.Params[0].GoType() != "*File" {
+		t.Errorf("GoType() = %v, want *File", restore.Params[0].GoType())
+	}
+	if len(def.warnings) != 1 {
+		t.Errorf("markFileParams() warnings = %v, want a warning about GET upload", def.warnings)
+	}
+}
+
+const uploadSnapshot = `{"webServices": [{"path": "api/qualityprofiles", "description": "Manage quality profiles.",
+	"actions": [{"key": "restore", "description": "Restore a quality profile", "since": "5.2", "post": true,
+		"params": [{"key": "backup", "description": "A profile backup file", "required": true}]},
+	{"key": "import", "description": "Import a quality profile", "since": "5.2", "post": true,
+		"params": [{"key": "name", "description": "Profile name", "required": true},
+			{"key": "rules", "description": "Rules file", "required": false}]}]}]}`
+
+// uploadTest runs in the generated package, the server reports the received form
+const uploadTest = `package sonar
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUploadNilRequest(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("ParseMultipartForm() error = %v", err)
+		}
+		fmt.Fprintf(w, "%d files", len(r.MultipartForm.File))
+	}))
+	defer ts.Close()
+	service := NewClient(ts.Client(), ts.URL, "", "").Qualityprofiles()
+
+	if _, err := service.Restore(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "file backup is required") {
+		t.Errorf("Restore(nil) error = %v, want required backup", err)
+	}
+	if _, err := service.Restore(context.Background(), &QualityprofilesServiceRestoreRequest{}); err == nil {
+		t.Errorf("Restore() without backup error = nil, want required backup")
+	}
+	if _, err := service.Restore(context.Background(), &QualityprofilesServiceRestoreRequest{Backup: &File{Name: "backup.xml"}}); err == nil || !strings.Contains(err.Error(), "file backup has no content") {
+		t.Errorf("Restore() without content error = %v, want no content", err)
+	}
+	if _, err := service.Import(context.Background(), &QualityprofilesServiceImportRequest{Rules: &File{Name: "rules.xml"}}); err == nil {
+		t.Errorf("Import() without content error = nil, want no content")
+	}
+	if _, err := service.Import(context.Background(), nil); err != nil {
+		t.Errorf("Import(nil) error = %v, want request without files", err)
+	}
+	resp, err := service.Restore(context.Background(), &QualityprofilesServiceRestoreRequest{
+		Backup: &File{Name: "backup.xml", Content: strings.NewReader("<profile/>")},
+	})
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	defer resp.Body.Close()
+	body := make([]byte, 16)
+	n, _ := resp.Body.Read(body)
+	if string(body[:n]) != "1 files" {
+		t.Errorf("Restore() sent %s, want 1 files", body[:n])
+	}
+}
+`
+
+func Test_files(t *testing.T) {
+	testGenerated(t, Options{FileParams: []string{"api/qualityprofiles/import?rules"}}, uploadSnapshot, uploadTest)
+}
@@ -0,0 +1,203 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	commonTemplateName        = "common.tpl"
+	commonFileName            = commonTemplateName + ".go"
+	versionClientTemplateName = "version-client.tpl"
+	versionPackagePrefix      = "sonar"
+	coreTemplateName          = "core.tpl"
+	coreFileName              = coreTemplateName + ".go"
+	// coreDir is the dir of the core packages next to the common and version packages, it's internal,
+	// so the request plumbing shared by them isn't a part of their api
+	coreDir = "internal"
+)
+
+// Target is a version to generate the client for, the definition is loaded from the snapshot file if it's set
+type Target struct {
+	Version  string
+	Snapshot string
+}
+
+// ParseTargets parses the comma separated list of target versions, every version can be followed by
+// the path of a definition snapshot, e.g. "8.9=defs/8.9.json,9.9"
+func ParseTargets(str string) ([]*Target, error) {
+	if strings.TrimSpace(str) == "" {
+		return []*Target{{}}, nil
+	}
+	targets := make([]*Target, 0)
+	for _, item := range strings.Split(str, ",") {
+		t := &Target{}
+		t.Version, t.Snapshot, _ = strings.Cut(strings.TrimSpace(item), "=")
+		if t.Version == "" {
+			return nil, fmt.Errorf("invalid target %q：version is required", item)
+		}
+		if err := new(Version).UnmarshalJSON([]byte(t.Version)); err != nil {
+			return nil, fmt.Errorf("invalid target %q：%w", item, err)
+		}
+		targets = append(targets, t)
+	}
+	return targets, nil
+}
+
+// versionPackageName returns the name of the package of the version, e.g. sonar89 for 8.9 and sonar10 for 10.0
+func versionPackageName(v *Version) string {
+	name := versionPackagePrefix + strconv.Itoa(v.Major())
+	if v.Minor() != 0 {
+		name += strconv.Itoa(v.Minor())
+	}
+	return name
+}
+
+// setVersionPackages names packages of the definitions after their versions,
+// the packages import the common package commonName from commonImport
+func setVersionPackages(defs []*APIDefinition, commonName, commonImport string) error {
+	names := make(map[string]string, len(defs))
+	for _, def := range defs {
+		name := versionPackageName(def.Version)
+		if name == commonName {
+			return fmt.Errorf("package name of target version %s is the name of the common package %s", def.Version, name)
+		}
+		if other, ok := names[name]; ok {
+			return fmt.Errorf("target versions %s and %s have the same package name %s", other, def.Version, name)
+		}
+		names[name] = def.Version.String()
+		def.setPackageName(name)
+		def.CommonImport = commonImport
+	}
+	return nil
+}
+
+// CoreImport returns the import path of the core package shared by the versioned packages and the common package
+func (def *APIDefinition) CoreImport() string {
+	return coreImport(def.CommonImport)
+}
+
+func coreImport(commonImport string) string {
+	return path.Dir(commonImport) + "/" + coreDir + "/" + path.Base(commonImport)
+}
+
+// commonData is passed to the common and core templates, Packages are names of the versioned packages
+type commonData struct {
+	PackageName string
+	Packages    []string
+	CoreImport  string
+}
+
+// renderCommon renders the common or the core package of versioned packages
+func (g *Generator) renderCommon(in io.Writer, templateName string, data *commonData) error {
+
+	buff := bytes.NewBuffer([]byte{})
+	buff.WriteString(generatedMarker + "\n\n")
+
+	commonTemplate, err := g.parseTemplate(templateName)
+	if err != nil {
+		return err
+	}
+
+	if err := commonTemplate.Execute(buff, data); err != nil {
+		return fmt.Errorf("failed to render common package：%w", err)
+	}
+
+	src := buff.Bytes()
+
+	formatted, err := format.Source(src)
+	if err != nil {
+		log.Printf("failed to format source of %s/%s.go: err:%s", data.PackageName, templateName, err.Error())
+		formatted = src
+	}
+
+	_, err = in.Write(formatted)
+	return err
+}
+
+// renderVersionClient renders the client of the versioned package wrapping the common client
+func (g *Generator) renderVersionClient(in io.Writer, data *APIDefinition) error {
+
+	buff := bytes.NewBuffer([]byte{})
+	buff.WriteString(generatedMarker + "\n\n")
+
+	versionClientTemplate, err := g.parseTemplate(versionClientTemplateName)
+	if err != nil {
+		return err
+	}
+
+	if err := versionClientTemplate.Execute(buff, data); err != nil {
+		return fmt.Errorf("failed to render client: %w", err)
+	}
+
+	src := moveDocLinks(buff.Bytes())
+
+	formatted, err := format.Source(src)
+	if err != nil {
+		log.Printf("failed to format source of %s/client.go: err:%s", data.PackageName, err.Error())
+		formatted = src
+	}
+
+	_, err = in.Write(formatted)
+	return err
+}
+
+// generateCommon generates the common package of versioned packages in the out directory
+func (g *Generator) generateCommon(name string, defs []*APIDefinition) ([]*fileChange, error) {
+	if err := checkOutput(g.opts.Out); err != nil {
+		return nil, err
+	}
+	packages := make([]string, 0, len(defs))
+	for _, def := range defs {
+		packages = append(packages, def.PackageName)
+	}
+	data := &commonData{PackageName: name, Packages: packages, CoreImport: defs[0].CoreImport()}
+	files := make(map[string][]byte)
+	coreFiles := make(map[string][]byte)
+	for templateName, rendered := range map[string]map[string][]byte{commonTemplateName: files, coreTemplateName: coreFiles} {
+		buff := new(bytes.Buffer)
+		if err := g.renderCommon(buff, templateName, data); err != nil {
+			return nil, err
+		}
+		rendered[templateName+".go"] = buff.Bytes()
+		stampFiles(rendered, g.stamp(defs...))
+	}
+
+	// packages of versions which aren't targeted anymore are removed, they are listed in the manifest of the previous run
+	previous, err := readManifest(g.packageDir(name))
+	if err != nil {
+		return nil, err
+	}
+	targeted := map[string]bool{name: true}
+	for _, pkg := range packages {
+		targeted[pkg] = true
+	}
+	changes := make([]*fileChange, 0)
+	for _, pkg := range previous.packages {
+		if targeted[pkg] {
+			continue
+		}
+		removed, err := g.writeChanges(pkg, map[string][]byte{}, nil)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, removed...)
+	}
+
+	core, err := g.writeChanges(coreDir+"/"+name, coreFiles, nil)
+	if err != nil {
+		return nil, err
+	}
+	common, err := g.writeChanges(name, files, packages)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, core...)
+	return append(changes, common...), nil
+}
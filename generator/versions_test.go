@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseTargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		str     string
+		want    []*Target
+		wantErr bool
+	}{
+		{name: "should use the server's version by default", str: "", want: []*Target{{}}},
+		{name: "should parse a single version", str: "7.1", want: []*Target{{Version: "7.1"}}},
+		{
+			name: "should parse versions with snapshots",
+			str:  "8.9=defs/8.9.json, 9.9",
+			want: []*Target{{Version: "8.9", Snapshot: "defs/8.9.json"}, {Version: "9.9"}},
+		},
+		{name: "should fail on a snapshot without version", str: "=defs/8.9.json", wantErr: true},
+		{name: "should fail on an invalid version", str: "8.9,latest", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTargets(tt.str)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseTargets() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTargets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_setVersionPackages(t *testing.T) {
+	defs := []*APIDefinition{
+		{Version: newVersion("8.9"), WebServices: []*WebService{{}}},
+		{Version: newVersion("10.0")},
+	}
+	if err := setVersionPackages(defs, "sonar", "example.com/sonar/common"); err != nil {
+		t.Fatalf("setVersionPackages() error = %v", err)
+	}
+	got := []string{defs[0].PackageName, defs[0].WebServices[0].PackageName, defs[1].PackageName, defs[1].CommonImport}
+	want := []string{"sonar89", "sonar89", "sonar10", "example.com/sonar/common"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("setVersionPackages() = %v, want %v", got, want)
+	}
+
+	duplicates := []*APIDefinition{{Version: newVersion("9.9")}, {Version: newVersion("9.9")}}
+	if err := setVersionPackages(duplicates, "sonar", ""); err == nil {
+		t.Errorf("setVersionPackages() expected error on the same package names")
+	}
+	if err := setVersionPackages(defs, "sonar10", ""); err == nil {
+		t.Errorf("setVersionPackages() expected error on the package name of the common package")
+	}
+}
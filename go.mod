@@ -1,3 +1,3 @@
-module main
+module github.com/RidgeA/sonarqube-api-client-gen
 
 go 1.20
@@ -1,13 +1,18 @@
 /*
-This is a tool to generate client library for SonarQube (https://www.sonarqube.org/) web-api
+This is a tool to generate client library for SonarQube (https://www.sonarqube.org/) web-api,
+it is a command line wrapper of the generator package.
 It allows to generate client library based on your server version.
 */
 package main
 
 import (
+	"errors"
 	"flag"
+	"fmt"
 	"log"
 	"os"
+
+	"github.com/RidgeA/sonarqube-api-client-gen/generator"
 )
 
 // flags
@@ -15,12 +20,27 @@ var (
 	host          string
 	deprecated    bool
 	internal      bool
+	strict        bool
 	targetVersion string
 	help          bool
 	out           string
-	auth          string
+	creds         credentials
 	packageName   string
 	templateDir   string
+	dryRun        bool
+	check         bool
+	cli           bool
+	examples      bool
+	outputFormat  string
+	cliImport     string
+	transport     transportOptions
+	docWidth      int
+	renameFile    string
+	fileParams    string
+	streamActions string
+	workers       int
+	configFile    string
+	inPlace       bool
 )
 
 var mainFlagsSet = flag.NewFlagSet("", flag.PanicOnError)
@@ -29,13 +49,46 @@ func parseFlags() {
 	mainFlagsSet.StringVar(&host, "host", "http://localhost:9000", "SonarQube server")
 	mainFlagsSet.BoolVar(&deprecated, "deprecated", false, "generate code for deprecated api methods (default: false)")
 	mainFlagsSet.BoolVar(&internal, "internal", false, "generate code for internal methods (default: false)")
-	mainFlagsSet.StringVar(&targetVersion, "target", "", "set target api version (default: server's version)")
+	mainFlagsSet.BoolVar(&strict, "strict", false, "fail on unknown fields in the api definition instead of ignoring them (default: false)")
+	mainFlagsSet.StringVar(&targetVersion, "target", "", "set target api version (default: server's version), a comma separated list generates a package per version, e.g. 8.9=defs/8.9.json,9.9 (a version can be followed by the api/webservices/list snapshot to load it from)")
 	mainFlagsSet.BoolVar(&help, "help", false, "show usage")
 	mainFlagsSet.StringVar(&out, "out", ".", "output directory")
-	mainFlagsSet.StringVar(&auth, "auth", "", "the header Authorization value,example: Basic YWRtaW46YWRtaW4=")
+	mainFlagsSet.StringVar(&outputFormat, "format", generator.FormatGo, "output format: go (client library), openapi (OpenAPI 3.1 document) or markdown (api reference)")
+	mainFlagsSet.BoolVar(&examples, "examples", false, "load response examples, they are used as response schemas of the openapi format (default: false)")
+	mainFlagsSet.BoolVar(&cli, "cli", false, "generate sonarctl command line tool in the cmd/sonarctl subdirectory of the package (default: false)")
+	mainFlagsSet.StringVar(&cliImport, "cli-import", "", "import path of the generated package used by the command line tool (default: resolved from go.mod)")
+	mainFlagsSet.StringVar(&creds.header, "auth", "", "the header Authorization value,example: Basic YWRtaW46YWRtaW4=")
+	mainFlagsSet.StringVar(&creds.token, "token", "", "user token (default: "+tokenEnv+" environment variable)")
+	mainFlagsSet.StringVar(&creds.tokenFile, "token-file", "", "file containing user token")
+	mainFlagsSet.StringVar(&creds.user, "user", "", "user login (default: "+userEnv+" environment variable)")
+	mainFlagsSet.StringVar(&creds.password, "password", "", "user password (default: "+passwordEnv+" environment variable)")
+	mainFlagsSet.StringVar(&creds.passwordFile, "password-file", "", "file containing user password")
 	mainFlagsSet.StringVar(&packageName, "package", "", "package name, if not set will be sonarqube_client")
-	mainFlagsSet.StringVar(&templateDir, "template", "tpl", "template directory")
+	mainFlagsSet.StringVar(&templateDir, "template", "", "template directory (default: built-in templates)")
+	mainFlagsSet.StringVar(&renameFile, "rename", "", "json file mapping api paths (api/projects, api/projects/search, api/projects/search?ps) to Go names")
+	mainFlagsSet.StringVar(&fileParams, "file-params", "", "comma separated list of params uploaded as files in addition to the known ones, e.g. api/plugins/upload?file")
+	mainFlagsSet.StringVar(&streamActions, "stream-actions", "", "comma separated list of actions returning binary or text content in addition to the known ones, e.g. api/plugins/download")
+	mainFlagsSet.IntVar(&workers, "workers", 0, "number of files rendered concurrently (default: number of CPUs)")
+	mainFlagsSet.IntVar(&docWidth, "doc-width", generator.DefaultDocWidth, "width of generated doc comments, 0 disables wrapping")
+	mainFlagsSet.BoolVar(&dryRun, "dry-run", false, "print files which would be created, updated or removed, without writing them")
+	mainFlagsSet.BoolVar(&check, "check", false, "exit with non-zero code if generated code on the disk differs from the one which would be generated")
+	mainFlagsSet.StringVar(&transport.caFile, "ca-file", "", "PEM bundle of additional certificate authorities to trust")
+	mainFlagsSet.StringVar(&transport.certFile, "cert", "", "PEM client certificate for mutual TLS")
+	mainFlagsSet.StringVar(&transport.keyFile, "key", "", "PEM client certificate key for mutual TLS")
+	mainFlagsSet.BoolVar(&transport.insecure, "insecure", false, "skip verification of the server certificate (default: false)")
+	mainFlagsSet.StringVar(&transport.proxy, "proxy", "", "proxy url (default: HTTP_PROXY/HTTPS_PROXY environment variables)")
+	mainFlagsSet.DurationVar(&transport.timeout, "timeout", generator.DefaultTimeout, "timeout of requests to the server")
+	mainFlagsSet.StringVar(&configFile, "config", "", "json file with options, keys are the option names, e.g. {\"target\": \"9.9\", \"internal\": true}, options of the command line take precedence")
+	mainFlagsSet.BoolVar(&inPlace, "in-place", os.Getenv(goPackageEnv) != "", "generate the package into the output directory itself instead of its subdirectory, the package name defaults to the one running go generate (default: true when run by go generate)")
 	mainFlagsSet.Parse(os.Args[1:])
+	if configFile != "" {
+		if err := applyConfig(mainFlagsSet, configFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if inPlace && packageName == "" {
+		packageName = os.Getenv(goPackageEnv)
+	}
 	if help {
 		mainFlagsSet.Usage()
 		os.Exit(0)
@@ -45,14 +98,84 @@ func parseFlags() {
 func main() {
 	parseFlags()
 
-	var err error
-	var def *apiDefinition
+	client, err := newHTTPClient(&transport)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	auth, err := creds.authorization()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	renames, err := generator.LoadRenames(renameFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	files, err := generator.ParseFileParams(fileParams)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	if def, err = loadAPI(nil, host, deprecated, internal, targetVersion, auth); err != nil {
+	streams, err := generator.ParseStreamActions(streamActions)
+	if err != nil {
 		log.Fatal(err)
 	}
 
-	if err = generateCode(def, out); err != nil {
+	targets, err := generator.ParseTargets(targetVersion)
+	if err != nil {
 		log.Fatal(err)
 	}
+	if cliImport != "" && len(targets) > 1 {
+		log.Fatal("-cli-import can't be used with multiple target versions")
+	}
+
+	mode := generator.ModeWrite
+	switch {
+	case dryRun && check:
+		log.Fatal("-dry-run and -check can't be used together")
+	case dryRun:
+		mode = generator.ModeDryRun
+	case check:
+		mode = generator.ModeCheck
+	}
+
+	g := generator.NewGenerator(generator.Options{
+		HTTPClient:    client,
+		Host:          host,
+		Auth:          auth,
+		Deprecated:    deprecated,
+		Internal:      internal,
+		Strict:        strict,
+		Examples:      examples,
+		PackageName:   packageName,
+		Renames:       renames,
+		FileParams:    files,
+		StreamActions: streams,
+		Out:           out,
+		InPlace:       inPlace,
+		Format:        outputFormat,
+		Mode:          mode,
+		TemplateDir:   templateDir,
+		DocWidth:      docWidth,
+		Workers:       workers,
+		CLI:           cli,
+		CLIImport:     cliImport,
+	})
+	changes, err := g.Run(targets)
+	if errors.Is(err, generator.ErrCLIImport) {
+		log.Fatalf("%s, use -cli-import", err)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, change := range changes {
+		switch {
+		case mode == generator.ModeDryRun:
+			fmt.Println(change)
+		case change.Op == generator.OpRemove:
+			log.Printf("removed stale file %s", change.Path)
+		}
+	}
 }
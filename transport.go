@@ -0,0 +1,90 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"time"
+
+	"github.com/RidgeA/sonarqube-api-client-gen/generator"
+)
+
+// transportOptions configures the http client used to fetch the api definition
+type transportOptions struct {
+	// caFile is a PEM bundle of additional trusted certificate authorities
+	caFile string
+	// certFile and keyFile are the PEM encoded client certificate and key used for mTLS
+	certFile string
+	keyFile  string
+	// insecure disables verification of the server certificate
+	insecure bool
+	// proxy overrides the proxy from HTTP_PROXY/HTTPS_PROXY/NO_PROXY environment variables
+	proxy   string
+	timeout time.Duration
+}
+
+func newHTTPClient(opts *transportOptions) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	tlsConfig, err := newTLSConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	if opts.proxy != "" {
+		proxyURL, err := neturl.Parse(opts.proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url (%s)：%w", opts.proxy, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	timeout := opts.timeout
+	if timeout == 0 {
+		timeout = generator.DefaultTimeout
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}, nil
+}
+
+func newTLSConfig(opts *transportOptions) (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: opts.insecure,
+	}
+
+	if opts.caFile != "" {
+		pem, err := os.ReadFile(opts.caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle：%w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle (%s)", opts.caFile)
+		}
+		config.RootCAs = pool
+	}
+
+	switch {
+	case opts.certFile != "" && opts.keyFile != "":
+		cert, err := tls.LoadX509KeyPair(opts.certFile, opts.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate：%w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	case opts.certFile != "" || opts.keyFile != "":
+		return nil, errors.New("client certificate and key must be set together")
+	}
+
+	return config, nil
+}
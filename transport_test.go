@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_newHTTPClient(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "9.9")
+	}))
+	defer ts.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		opts          *transportOptions
+		wantClientErr bool
+		wantErr       bool
+	}{
+		{
+			name:    "should reject unknown certificate authority by default",
+			opts:    &transportOptions{},
+			wantErr: true,
+		},
+		{
+			name: "should trust certificate authority from the CA bundle",
+			opts: &transportOptions{caFile: caFile},
+		},
+		{
+			name: "should skip verification if insecure",
+			opts: &transportOptions{insecure: true},
+		},
+		{
+			name:          "should fail if client key is missing",
+			opts:          &transportOptions{certFile: caFile},
+			wantClientErr: true,
+		},
+		{
+			name:          "should fail if proxy url is invalid",
+			opts:          &transportOptions{proxy: "://proxy"},
+			wantClientErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := newHTTPClient(tt.opts)
+			if (err != nil) != tt.wantClientErr {
+				t.Fatalf("newHTTPClient() error = %v, wantClientErr %v", err, tt.wantClientErr)
+			}
+			if err != nil {
+				return
+			}
+			resp, err := client.Get(ts.URL + "/api/server/version")
+			if err == nil {
+				resp.Body.Close()
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("client.Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}